@@ -0,0 +1,217 @@
+package lru
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// actorBatch is the maximum number of queued operations an Actor applies before publishing a new view.
+const actorBatch = 64
+
+type actorOp[K comparable, V any] struct {
+	clear bool
+	key   K
+	val   V
+	reply chan error
+}
+
+// An actorView is the immutable snapshot of an Actor's Cache that Get reads, except that entries are
+// marked gone when the Cache removes them, so that Get stops serving them before the next batch.
+type actorView[K comparable, V any] struct {
+	keys    map[K]int // index into entries
+	entries []actorEntry[V]
+}
+
+type actorEntry[V any] struct {
+	val  V
+	exp  int64 // see node.exp
+	gone atomic.Bool
+}
+
+// An Actor is an alternative to a Cache in which every mutation is applied by a single goroutine, and
+// reads are served lock-free from an immutable view of the cache's contents that the goroutine publishes
+// after each batch of mutations. Put and Clear block until their effects are visible to Get. Entries that
+// the Cache removes in between, for instance by expiry with WithJanitor, are no longer served from the
+// moment they are removed, and Get treats expired entries, and entries rejected by the func set with
+// WithValidator, as missing. Hits are reported to the goroutine through a lossy buffer, so under heavy
+// load some hits may not promote their entry. Because the view is rebuilt after every batch, an Actor suits caches that are read far more
+// often than they are written. An Actor should not be copied, and must be closed with Close to stop its
+// goroutine.
+type Actor[K comparable, V any] struct {
+	c      *Cache[K, V]
+	view   atomic.Pointer[actorView[K, V]]
+	ops    chan actorOp[K, V]
+	hits   chan K
+	done   chan struct{}
+	closed chan struct{}
+	once   sync.Once
+}
+
+var _ Cacher[int, int] = (*Actor[int, int])(nil)
+
+// ErrClosed is returned by operations on an Actor that has been closed.
+var ErrClosed = errors.New("lru: use of closed Actor")
+
+// NewActor creates a new Actor with a capacity of cap items and starts its goroutine. If evict is
+// non-nil, it is called by that goroutine each time a key-value pair is evicted. Any opts are applied
+// to the underlying Cache. The func set with WithValidator, if any, is also called by Get, without the
+// Cache's lock.
+func NewActor[K comparable, V any](cap uint64, evict func(K, V) error, opts ...Option[K, V]) *Actor[K, V] {
+	a := &Actor[K, V]{
+		c:      New(cap, evict, opts...),
+		ops:    make(chan actorOp[K, V]),
+		hits:   make(chan K, 1024),
+		done:   make(chan struct{}),
+		closed: make(chan struct{}),
+	}
+	a.view.Store(&actorView[K, V]{keys: make(map[K]int)})
+	a.c.lock()
+	// the Cache may remove entries by itself, for instance from a janitor goroutine
+	a.c.watches = append(a.c.watches, &watch[K, V]{removals: true, match: a.cached, fn: a.drop})
+	a.c.unlock()
+	go a.run()
+	return a
+}
+
+// Get returns the value associated with key in the most recently published view and a bool, which is
+// true if the key was found and false otherwise. An entry the Cache has removed since, or that has expired
+// or is rejected by the validator, is not found.
+func (a *Actor[K, V]) Get(key K) (V, bool) {
+	v := a.view.Load()
+	i, ok := v.keys[key]
+	if !ok {
+		return *new(V), false
+	}
+	e := &v.entries[i]
+	if e.gone.Load() {
+		return *new(V), false
+	}
+	ok = (e.exp == 0 || a.c.now() < e.exp) && (a.c.validate == nil || a.c.validate(key, e.val))
+	// the hit also has the goroutine remove the entry if it is no longer valid
+	select {
+	case a.hits <- key:
+	default:
+	}
+	if !ok {
+		return *new(V), false
+	}
+	return e.val, true
+}
+
+// Put adds a key-value pair to the Actor and waits until it is visible to Get. It returns any error
+// returned by the evict func, or ErrClosed if the Actor has been closed.
+func (a *Actor[K, V]) Put(key K, val V) error {
+	return a.do(actorOp[K, V]{key: key, val: val})
+}
+
+// Clear evicts all entries from the Actor (calling the evict func if it exists) and waits until the
+// published view is empty.
+func (a *Actor[K, V]) Clear() error {
+	return a.do(actorOp[K, V]{clear: true})
+}
+
+// Close stops the Actor's goroutine, then closes the underlying Cache, stopping any goroutines started
+// by the opts passed to NewActor, and returns any error from doing so. Get continues to serve the last
+// published view, but Put and Clear return ErrClosed.
+func (a *Actor[K, V]) Close() error {
+	a.once.Do(func() { close(a.done) })
+	<-a.closed
+	return a.c.Close()
+}
+
+func (a *Actor[K, V]) do(op actorOp[K, V]) error {
+	op.reply = make(chan error, 1)
+	select {
+	case a.ops <- op:
+		return <-op.reply
+	case <-a.closed:
+		return ErrClosed
+	}
+}
+
+func (a *Actor[K, V]) run() {
+	defer close(a.closed)
+	batch := make([]actorOp[K, V], 0, actorBatch)
+	errs := make([]error, 0, actorBatch)
+	for {
+		select {
+		case op := <-a.ops:
+			batch = append(batch[:0], op)
+		gather:
+			for len(batch) < actorBatch {
+				select {
+				case op := <-a.ops:
+					batch = append(batch, op)
+				default:
+					break gather
+				}
+			}
+			a.drainHits()
+			errs = errs[:0]
+			for _, op := range batch {
+				if op.clear {
+					errs = append(errs, a.c.Clear())
+				} else {
+					errs = append(errs, a.c.Put(op.key, op.val))
+				}
+			}
+			// reply only once the view is published, so that writes are visible when Put returns
+			a.publish()
+			for i, op := range batch {
+				op.reply <- errs[i]
+			}
+			clear(batch)
+		case key := <-a.hits:
+			a.c.Get(key)
+		case <-a.done:
+			return
+		}
+	}
+}
+
+// drainHits applies the recency updates of all buffered hits.
+func (a *Actor[K, V]) drainHits() {
+	for {
+		select {
+		case key := <-a.hits:
+			a.c.Get(key)
+		default:
+			return
+		}
+	}
+}
+
+// publish replaces the view read by Get with a copy of the current contents of the Cache. The view is
+// stored before the lock is released, so that drop cannot mark entries of a view that is being replaced.
+func (a *Actor[K, V]) publish() {
+	c := a.c
+	c.rlock()
+	defer c.runlock()
+	v := &actorView[K, V]{
+		keys:    make(map[K]int, c.len),
+		entries: make([]actorEntry[V], c.len),
+	}
+	live := c.live()
+	for i := range c.data[:c.len] {
+		if n := &c.data[i]; live.ok(n) {
+			v.entries[len(v.keys)].val = n.val
+			v.entries[len(v.keys)].exp = n.exp
+			v.keys[n.key] = len(v.keys)
+		}
+	}
+	a.view.Store(v)
+}
+
+// cached reports whether key is in the current view.
+func (a *Actor[K, V]) cached(key K) bool {
+	_, ok := a.view.Load().keys[key]
+	return ok
+}
+
+// drop marks the entry of a key that the Cache has removed as gone from the current view. It is called
+// by the Cache while it is locked for writing.
+func (a *Actor[K, V]) drop(e WatchEvent[K, V]) {
+	v := a.view.Load()
+	v.entries[v.keys[e.Key]].gone.Store(true)
+}
@@ -0,0 +1,51 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActorExpiry(t *testing.T) {
+	evicted := make(chan int, 1)
+	a := NewActor(4, func(key, _ int) error {
+		evicted <- key
+		return nil
+	}, WithTTL[int, int](time.Millisecond), WithJanitor[int, int](time.Millisecond, nil))
+	defer a.Close()
+	if err := a.Put(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-evicted:
+	case <-time.After(time.Second):
+		t.Fatal("janitor did not evict the expired entry")
+	}
+	if val, ok := a.Get(1); ok {
+		t.Errorf("Get(1) = %v, true after the entry was evicted", val)
+	}
+}
+
+func TestActorLazyExpiry(t *testing.T) {
+	a := NewActor[int, int](4, nil, WithTTL[int, int](time.Millisecond))
+	defer a.Close()
+	if err := a.Put(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if val, ok := a.Get(1); ok {
+		t.Errorf("Get(1) = %v, true after the entry expired", val)
+	}
+}
+
+func TestActorValidator(t *testing.T) {
+	a := NewActor[int, int](4, nil, WithValidator(func(_, val int) bool { return val > 0 }))
+	defer a.Close()
+	a.Put(1, 1)
+	a.Put(2, 0)
+	if _, ok := a.Get(1); !ok {
+		t.Error("Get(1) missed a valid entry")
+	}
+	if _, ok := a.Get(2); ok {
+		t.Error("Get(2) found an invalid entry")
+	}
+}
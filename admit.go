@@ -0,0 +1,35 @@
+package lru
+
+import "errors"
+
+// ErrNotAdmitted is returned by Put and similar methods when the func set with WithAdmission refuses to
+// cache a new key. It tells a caller that the value was deliberately not cached, as opposed to failing.
+var ErrNotAdmitted = errors.New("lru: entry not admitted")
+
+// AdmissionInfo describes the Cache at the time a new entry is considered for admission.
+type AdmissionInfo struct {
+	Weight uint64 // the weight of the new entry; see WithWeigher
+	Free   uint64 // the weight that can be added without evicting; see WithMaxWeight
+	Len    int    // the number of cached entries
+	Cap    uint64 // the capacity of the Cache
+}
+
+// WithAdmission sets a func that decides whether a key that is not cached may be added to the Cache, for
+// instance because its weight is small relative to the remaining budget or because it has been requested
+// often enough. When admit returns false, nothing is evicted and the write returns ErrNotAdmitted. Writes
+// to keys that are already cached are always admitted. admit is called while the Cache is locked, so it
+// must not call methods on the Cache.
+func WithAdmission[K comparable, V any](admit func(K, V, AdmissionInfo) bool) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.admit = admit
+	}
+}
+
+// admitted reports whether a new entry of weight w may be added for key.
+func (c *Cache[K, V]) admitted(key K, val V, w uint64) bool {
+	info := AdmissionInfo{Weight: w, Len: c.len, Cap: c.cap}
+	if c.maxWeight > c.weight {
+		info.Free = c.maxWeight - c.weight
+	}
+	return c.admit(key, val, info)
+}
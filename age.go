@@ -0,0 +1,73 @@
+package lru
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// An AgeHistogram counts the entries of a Cache by age. Bucket i of Inserted and Accessed counts the
+// entries whose age is greater than Bounds[i-1] and at most Bounds[i]; the last bucket counts the entries
+// older than every bound.
+type AgeHistogram struct {
+	Bounds   []time.Duration // upper bounds of the buckets, in ascending order
+	Inserted []int           // entries by time since insertion, or nil without recorded write times
+	Accessed []int           // entries by time since the last access, or nil without WithAccessTimes
+}
+
+// WithAccessTimes records the time of every access of an entry, so that Ages can report how long ago
+// entries were last used. Without it, accesses only update a logical clock, which is cheaper because it
+// does not read the system clock.
+func WithAccessTimes[K comparable, V any]() Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.accessed = true
+	}
+}
+
+// WithWriteTimes records the time of every write of an entry, as needed by ExpireBefore, the Inserted
+// buckets of Ages, and the Written time reported by GetEntry. Caches using WithTTL, WithMaxAge, or
+// WithAccessTimes record write times regardless, since their writes read the system clock anyway; others
+// only read it for writes with a TTL of their own, such as PutWithTTL.
+func WithWriteTimes[K comparable, V any]() Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.stamped = true
+	}
+}
+
+// Ages returns a histogram of the unexpired entries of the Cache by age, with one bucket per bound plus
+// one for older entries. The bounds need not be sorted. Ages is computed on demand by visiting every
+// entry under a read lock, so it is meant for occasional use, such as feeding a dashboard.
+func (c *Cache[K, V]) Ages(bounds ...time.Duration) AgeHistogram {
+	bounds = slices.Clone(bounds)
+	slices.Sort(bounds)
+	h := AgeHistogram{Bounds: bounds}
+	if c.stamped {
+		h.Inserted = make([]int, len(bounds)+1)
+	}
+	if c.accessed {
+		h.Accessed = make([]int, len(bounds)+1)
+	}
+
+	c.rlock()
+	defer c.runlock()
+	now := c.now()
+	for i := range c.data[:c.len] {
+		if c.expired(i) {
+			continue
+		}
+		n := &c.data[i]
+		if h.Inserted != nil {
+			h.Inserted[bucket(bounds, now-n.born)]++
+		}
+		if h.Accessed != nil {
+			h.Accessed[bucket(bounds, now-atomic.LoadInt64(&n.used))]++ // see shared
+		}
+	}
+	return h
+}
+
+// bucket returns the index of the first bound that is not less than age, or len(bounds) if there is none.
+func bucket(bounds []time.Duration, age int64) int {
+	i, _ := slices.BinarySearch(bounds, time.Duration(age))
+	return i
+}
@@ -0,0 +1,62 @@
+//go:build !lrudebug
+
+// The owner tracking of lrudebug builds allocates on every lock.
+
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNoAllocs guards the guarantee in the Cache doc that hits and iteration do not allocate.
+func TestNoAllocs(t *testing.T) {
+	c := New[int, int](100, nil, WithTTL[int, int](time.Hour))
+	for i := range 100 {
+		c.Put(i, i)
+	}
+	var sink int
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"Get", func() { sink, _ = c.Get(50) }},
+		{"Put", func() { c.Put(50, 51) }},
+		{"All", func() {
+			for k, v := range c.All() {
+				sink += k + v
+			}
+		}},
+		{"Keys", func() {
+			for k := range c.Keys() {
+				sink += k
+			}
+		}},
+		{"Values", func() {
+			for v := range c.Values() {
+				sink += v
+			}
+		}},
+		{"Ordered", func() {
+			for k, v := range c.Ordered() {
+				sink += k + v
+			}
+		}},
+		{"OrderedKeys", func() {
+			for k := range c.OrderedKeys() {
+				sink += k
+			}
+		}},
+		{"Backward", func() {
+			for k, v := range c.Backward() {
+				sink += k + v
+			}
+		}},
+	}
+	for _, tt := range tests {
+		if n := testing.AllocsPerRun(100, tt.f); n != 0 {
+			t.Errorf("%s: got %v allocations, want 0", tt.name, n)
+		}
+	}
+	_ = sink
+}
@@ -0,0 +1,187 @@
+package lru
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+)
+
+// arcList identifies which of an ARC's four lists an entry is on.
+type arcList uint8
+
+const (
+	arcT1 arcList = iota // cached, accessed once
+	arcT2                // cached, accessed more than once
+	arcB1                // ghost, evicted from T1
+	arcB2                // ghost, evicted from T2
+)
+
+type arcEntry[K comparable, V any] struct {
+	key K
+	val V // zero for ghosts
+	in  arcList
+}
+
+// An ARC is a generic, concurrency-safe cache that uses the Adaptive Replacement Cache policy. It keeps
+// entries that have been accessed once apart from those that have been accessed more often, and divides
+// its capacity between the two according to a target it adapts as it goes: it also remembers the keys,
+// but not the values, of as many recently evicted entries, and a Put of one of those keys shifts the
+// target towards the part of the cache it was evicted from. An ARC therefore resists scans like a
+// segmented LRU while adapting to workloads that favor recency or frequency, at the cost of tracking up to
+// twice its capacity in keys. An ARC should not be copied.
+type ARC[K comparable, V any] struct {
+	m     sync.Mutex
+	cap   int
+	p     int // target length of T1
+	evict func(K, V) error
+	keys  map[K]*list.Element
+	lists [4]list.List // indexed by arcList; the front of each list is its most-recently used end
+}
+
+var _ Cacher[int, int] = (*ARC[int, int])(nil)
+
+// NewARC creates a new ARC with a capacity of cap items. If evict is non-nil, it is called each time a
+// key-value pair is evicted.
+func NewARC[K comparable, V any](cap uint64, evict func(K, V) error) *ARC[K, V] {
+	return &ARC[K, V]{
+		cap:   int(cap),
+		evict: evict,
+		keys:  make(map[K]*list.Element, 2*cap),
+	}
+}
+
+// Get returns the cached value associated with key and a bool, which is true if the key was found
+// and false otherwise. A hit moves the entry to the part of the ARC for entries accessed more than once.
+func (a *ARC[K, V]) Get(key K) (V, bool) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	el, ok := a.keys[key]
+	if !ok {
+		return *new(V), false
+	}
+	e := el.Value.(*arcEntry[K, V])
+	if e.in >= arcB1 {
+		return *new(V), false
+	}
+	a.move(el, arcT2)
+	return e.val, true
+}
+
+// Put adds a key-value pair to the ARC, counting as an access if the key is already cached. If the ARC is
+// full and the key is not already cached, it evicts an entry. If an eviction occurs and the evict func is
+// non-nil, Put returns any error returned by evict. Otherwise, the returned error will be nil.
+func (a *ARC[K, V]) Put(key K, val V) error {
+	a.m.Lock()
+	defer a.m.Unlock()
+	var err error
+
+	if a.cap == 0 {
+		return err
+	}
+
+	t1, t2 := &a.lists[arcT1], &a.lists[arcT2]
+	b1, b2 := &a.lists[arcB1], &a.lists[arcB2]
+	el, ok := a.keys[key]
+	if ok {
+		e := el.Value.(*arcEntry[K, V])
+		switch e.in {
+		case arcB1:
+			// the key was evicted too soon from T1, so give T1 more room
+			a.p = min(a.p+max(b2.Len()/b1.Len(), 1), a.cap)
+			err = a.replace(false)
+		case arcB2:
+			a.p = max(a.p-max(b1.Len()/b2.Len(), 1), 0)
+			err = a.replace(true)
+		}
+		e.val = val
+		a.move(el, arcT2)
+		return err
+	}
+
+	switch {
+	case t1.Len()+b1.Len() == a.cap:
+		if t1.Len() < a.cap {
+			a.drop(b1.Back())
+			err = a.replace(false)
+		} else {
+			// B1 is empty, so the victim is not remembered
+			victim := t1.Back()
+			e := victim.Value.(*arcEntry[K, V])
+			a.drop(victim)
+			if a.evict != nil {
+				err = a.evict(e.key, e.val)
+			}
+		}
+	case t1.Len()+t2.Len()+b1.Len()+b2.Len() >= a.cap:
+		if t1.Len()+t2.Len()+b1.Len()+b2.Len() == 2*a.cap {
+			a.drop(b2.Back())
+		}
+		err = a.replace(false)
+	}
+	a.keys[key] = t1.PushFront(&arcEntry[K, V]{key: key, val: val, in: arcT1})
+	return err
+}
+
+// replace makes room for an entry, when the ARC is full, by evicting the least-recently used entry of T1
+// or of T2, whichever is over its target length, and remembering its key as a ghost. inB2 reports whether
+// the entry being made room for is a ghost of T2.
+func (a *ARC[K, V]) replace(inB2 bool) error {
+	t1, t2 := &a.lists[arcT1], &a.lists[arcT2]
+	if t1.Len()+t2.Len() < a.cap {
+		return nil
+	}
+	victim, ghost := t2.Back(), arcB2
+	if n := t1.Len(); n > 0 && (n > a.p || inB2 && n == a.p) {
+		victim, ghost = t1.Back(), arcB1
+	}
+	e := victim.Value.(*arcEntry[K, V])
+	val := e.val
+	e.val = *new(V)
+	a.move(victim, ghost)
+	if a.evict != nil {
+		return a.evict(e.key, val)
+	}
+	return nil
+}
+
+// move moves el to the front of list to.
+func (a *ARC[K, V]) move(el *list.Element, to arcList) {
+	e := el.Value.(*arcEntry[K, V])
+	if e.in == to {
+		a.lists[to].MoveToFront(el)
+		return
+	}
+	a.lists[e.in].Remove(el)
+	e.in = to
+	a.keys[e.key] = a.lists[to].PushFront(e)
+}
+
+// drop forgets the entry of el entirely.
+func (a *ARC[K, V]) drop(el *list.Element) {
+	e := el.Value.(*arcEntry[K, V])
+	a.lists[e.in].Remove(el)
+	delete(a.keys, e.key)
+}
+
+// Clear evicts all entries from the ARC (calling the evict func if it exists) and resets it, forgetting
+// the keys of evicted entries too. A cleared ARC is safe for re-use.
+func (a *ARC[K, V]) Clear() error {
+	a.m.Lock()
+	defer a.m.Unlock()
+	var err error
+
+	if a.evict != nil {
+		for _, in := range []arcList{arcT1, arcT2} {
+			for el := a.lists[in].Front(); el != nil; el = el.Next() {
+				e := el.Value.(*arcEntry[K, V])
+				err = errors.Join(err, a.evict(e.key, e.val))
+			}
+		}
+	}
+	for i := range a.lists {
+		a.lists[i].Init()
+	}
+	clear(a.keys)
+	a.p = 0
+	return err
+}
@@ -0,0 +1,97 @@
+package lru
+
+import "sync"
+
+// WithAsyncEvict hands entries leaving the Cache to a pool of workers goroutines, which call the evict
+// func and the recycler (see WithRecycler) without holding the Cache's lock. A slow evict func then no
+// longer stalls other operations, and it may call methods on the Cache. Entries wait for a worker on a
+// queue with room for queueSize entries, so that removing an entry does not wait for the workers. While
+// the queue is full, entries are disposed of synchronously instead, as they would be without
+// WithAsyncEvict, which keeps a slow evict func from piling up evicted values; an evict func that calls
+// methods on the Cache must then be set with WithEvictLocked. If queueSize is not positive, the queue
+// is unbounded. Errors returned by evict for queued entries are passed to onErr, if it is non-nil,
+// rather than to the caller, and those entries are not parked by WithEvictRetry. Batches for the func
+// set with WithBatchEvict are still delivered synchronously. The Cache must be closed with Close to
+// stop the workers, after which entries are again disposed of synchronously.
+func WithAsyncEvict[K comparable, V any](workers, queueSize int, onErr func(error)) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.workers = max(workers, 1)
+		c.evictCap = max(queueSize, 0)
+		c.evictq = make([]Entry[K, V], 0, c.evictCap)
+		c.ready = sync.NewCond(&c.queued)
+		c.idle = sync.NewCond(&c.queued)
+		if onErr != nil {
+			c.onErr = onErr
+		}
+	}
+}
+
+// FlushEvictions waits until the workers started by WithAsyncEvict have disposed of every entry handed
+// to them so far. It must not be called by the evict func.
+func (c *Cache[K, V]) FlushEvictions() {
+	if c.workers == 0 {
+		return
+	}
+	c.queued.Lock()
+	for c.inflight > 0 {
+		c.idle.Wait()
+	}
+	c.queued.Unlock()
+}
+
+// dispatch hands an entry to the eviction workers and reports whether it did so, which it does not if the
+// queue is full.
+func (c *Cache[K, V]) dispatch(key K, val V, reason Reason) bool {
+	c.queued.Lock()
+	if c.evictCap > 0 && len(c.evictq) >= c.evictCap {
+		c.queued.Unlock()
+		return false
+	}
+	c.evictq = append(c.evictq, Entry[K, V]{Key: key, Val: val, Reason: reason})
+	c.inflight++
+	c.ready.Signal()
+	c.queued.Unlock()
+	return true
+}
+
+// stopWorkers makes the eviction workers exit once the queue is empty.
+func (c *Cache[K, V]) stopWorkers() {
+	c.queued.Lock()
+	c.draining = true
+	c.ready.Broadcast()
+	c.queued.Unlock()
+}
+
+func (c *Cache[K, V]) evictWorker(<-chan struct{}) {
+	c.queued.Lock()
+	defer c.queued.Unlock()
+	for {
+		for len(c.evictq) == 0 {
+			if c.draining {
+				return
+			}
+			c.ready.Wait()
+		}
+		e := c.evictq[0]
+		c.evictq[0] = Entry[K, V]{}
+		c.evictq = c.evictq[1:]
+		c.queued.Unlock()
+
+		var err error
+		switch {
+		case c.evictLocked != nil:
+			err = c.evictLocked(&Locked[K, V]{c: c}, e.Key, e.Val, e.Reason)
+		case c.evict != nil:
+			err = c.evict(e.Key, e.Val, e.Reason)
+		}
+		if err != nil && c.onErr != nil {
+			c.onErr(err)
+		}
+		c.recycleVal(e.Val)
+
+		c.queued.Lock()
+		if c.inflight--; c.inflight == 0 {
+			c.idle.Broadcast()
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestAsyncEvictFull checks that entries are disposed of synchronously while the queue is full.
+func TestAsyncEvictFull(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var m sync.Mutex
+	var evicted []int
+	c := New(1, func(key, _ int) error {
+		if key == 0 {
+			close(started)
+			<-release
+		}
+		m.Lock()
+		evicted = append(evicted, key)
+		m.Unlock()
+		return nil
+	}, WithAsyncEvict[int, int](1, 1, nil))
+	defer c.Close()
+
+	c.Put(0, 0)
+	c.Put(1, 1) // evicts 0, which the worker is stuck on
+	<-started
+	c.Put(2, 2) // evicts 1, which fills the queue
+	c.Put(3, 3) // evicts 2, for which there is no room
+	m.Lock()
+	got := append([]int(nil), evicted...)
+	m.Unlock()
+	if len(got) != 1 || got[0] != 2 {
+		t.Errorf("evicted %v while the queue was full, want [2]", got)
+	}
+	close(release)
+	c.FlushEvictions()
+	if len(evicted) != 3 {
+		t.Errorf("evicted %v after flushing, want 3 entries", evicted)
+	}
+}
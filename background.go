@@ -0,0 +1,129 @@
+package lru
+
+import "errors"
+
+// evictChunk is the number of entries the background evictor evicts per lock acquisition.
+const evictChunk = 64
+
+// WithBackgroundEviction moves the batch eviction configured with WithWatermarks onto a background
+// goroutine: a Put that reaches the high watermark only wakes the goroutine, which then evicts down to the
+// low watermark in small chunks, releasing the lock between them. Puts only evict synchronously, one
+// entry at a time, if the Cache reaches its capacity before the goroutine catches up. If onErr is
+// non-nil, it is called with any errors returned by the evict func on the goroutine. The Cache must be
+// closed with Close to stop the goroutine.
+func WithBackgroundEviction[K comparable, V any](onErr func(error)) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.wake = make(chan struct{}, 1)
+		if onErr != nil {
+			c.onErr = onErr
+		}
+	}
+}
+
+// Close stops the Cache's background goroutines, if it has any, and waits for them to exit. The Cache
+// remains usable after Close, but background work, such as eviction configured with
+// WithBackgroundEviction, is no longer performed. Entries already handed to the workers of
+// WithAsyncEvict, and entries waiting out the grace period set by WithEvictDelay, are disposed of before
+// Close returns. Close returns any errors returned by the evict func for the latter.
+func (c *Cache[K, V]) Close() error {
+	var err error
+	c.stopOnce.Do(func() {
+		c.lock()
+		c.closed = true
+		err = c.flushGraced()
+		c.unlock()
+		if c.stop != nil {
+			close(c.stop)
+		}
+		if c.workers > 0 {
+			c.stopWorkers()
+		}
+	})
+	c.wg.Wait()
+	return err
+}
+
+// EvictionBacklog returns the number of entries the background evictor has yet to evict to bring the
+// Cache down to its low watermark (see WithBackgroundEviction).
+func (c *Cache[K, V]) EvictionBacklog() int {
+	c.rlock()
+	defer c.runlock()
+	if !c.pending || uint64(c.len) <= c.low {
+		return 0
+	}
+	return c.len - int(c.low)
+}
+
+// start launches the background goroutines required by the Cache's options.
+func (c *Cache[K, V]) start() {
+	if c.wake != nil {
+		c.goBackground(c.evictLoop)
+	}
+	if c.janitor > 0 {
+		c.goBackground(c.janitorLoop)
+	}
+	for range c.workers {
+		c.goBackground(c.evictWorker)
+	}
+}
+
+// goBackground runs f on a new goroutine that Close waits for. f must return once stop is closed.
+func (c *Cache[K, V]) goBackground(f func(stop <-chan struct{})) {
+	if c.stop == nil {
+		c.stop = make(chan struct{})
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		f(c.stop)
+	}()
+}
+
+// signalEviction wakes the background evictor without blocking.
+func (c *Cache[K, V]) signalEviction() {
+	c.pending = true
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Cache[K, V]) evictLoop(stop <-chan struct{}) {
+	for {
+		select {
+		case <-c.wake:
+			if err := c.evictBacklog(stop); err != nil && c.onErr != nil {
+				c.onErr(err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// evictBacklog evicts entries in chunks until the Cache is down to its low watermark.
+func (c *Cache[K, V]) evictBacklog(stop <-chan struct{}) error {
+	var err error
+	for {
+		select {
+		case <-stop:
+			return err
+		default:
+		}
+		c.lock()
+		target := c.low
+		if uint64(c.len) > c.low+evictChunk {
+			target = uint64(c.len) - evictChunk
+		}
+		err = errors.Join(err, c.shrink(target))
+		// stop early if shrink ran out of evictable entries
+		done := uint64(c.len) <= c.low || uint64(c.len) > target
+		if done {
+			c.pending = false
+		}
+		c.unlock()
+		if done {
+			return err
+		}
+	}
+}
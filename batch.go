@@ -0,0 +1,75 @@
+package lru
+
+import "time"
+
+// An Entry is a key-value pair that left a Cache, as delivered to a batch evict func.
+type Entry[K comparable, V any] struct {
+	Key    K
+	Val    V
+	Reason Reason
+}
+
+// WithBatchEvict sets an evict func that receives every entry evicted by a single operation, such as
+// Clear, Resize, RemoveExpired, or a Put that reaches the high watermark (see WithWatermarks), in one
+// call, so that it can issue a bulk write to a downstream store. Operations that evict a single entry
+// call it with a slice of length one. The slice is only valid for the duration of the call. It replaces
+// the evict func passed to New. If the call fails and WithEvictRetry is set, the entries in the batch are
+// parked on the retry queue and retried one at a time.
+func WithBatchEvict[K comparable, V any](evict func([]Entry[K, V]) error) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.batch = evict
+		c.evictLocked = nil
+		c.evict = func(key K, val V, reason Reason) error {
+			return evict([]Entry[K, V]{{Key: key, Val: val, Reason: reason}})
+		}
+	}
+}
+
+// beginBatch makes discardNow collect entries for the batch evict func instead of calling the evict func
+// for each one, until the matching call to endBatch. Batches may nest, in which case the outermost one
+// collects the entries of all of them.
+func (c *Cache[K, V]) beginBatch() {
+	if c.batch != nil {
+		c.batching++
+	}
+}
+
+// endBatch ends a batch begun by beginBatch. At the end of the outermost batch, it passes the entries
+// collected to the batch evict func and disposes of them as discardNow would.
+func (c *Cache[K, V]) endBatch() error {
+	if c.batching == 0 {
+		return nil
+	}
+	if c.batching--; c.batching > 0 || len(c.batched) == 0 {
+		return nil
+	}
+	defer func() {
+		clear(c.batched)
+		c.batched = c.batched[:0]
+	}()
+
+	err := c.batch(c.batched)
+	if err == nil {
+		for _, e := range c.batched {
+			c.recycleVal(e.Val)
+		}
+		return nil
+	}
+	at := time.Now().Add(c.backoff)
+	for i, e := range c.batched {
+		if len(c.retries) >= c.retrySize {
+			// the rest of the batch cannot be parked, so it is dropped
+			for _, e := range c.batched[i:] {
+				c.recycleVal(e.Val)
+			}
+			return err
+		}
+		c.retries = append(c.retries, retryEntry[K, V]{
+			key:    e.Key,
+			val:    e.Val,
+			reason: e.Reason,
+			at:     at,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package lru
+
+import "testing"
+
+// The benchmarks measure the hit and miss paths of a Cache without options, which should not pay for
+// the features it does not use.
+
+func BenchmarkGetHit(b *testing.B) {
+	c := New[int, int](1024, nil)
+	for i := range 1024 {
+		c.Put(i, i)
+	}
+	b.ResetTimer()
+	for i := range b.N {
+		c.Get(i & 1023)
+	}
+}
+
+func BenchmarkPutHit(b *testing.B) {
+	c := New[int, int](1024, nil)
+	for i := range 1024 {
+		c.Put(i, i)
+	}
+	b.ResetTimer()
+	for i := range b.N {
+		c.Put(i&1023, i)
+	}
+}
+
+func BenchmarkPutMiss(b *testing.B) {
+	c := New[int, int](1024, nil)
+	b.ResetTimer()
+	for i := range b.N {
+		c.Put(i, i)
+	}
+}
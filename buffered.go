@@ -0,0 +1,167 @@
+package lru
+
+import (
+	"errors"
+	"hash/maphash"
+	"sync"
+)
+
+// ErrDropped is returned by Buffered.Put when the write buffer is full and the write was discarded.
+var ErrDropped = errors.New("lru: write buffer full, write dropped")
+
+// bufferStripes is the number of independently locked stripes a Buffered's write buffer is split into.
+const bufferStripes = 16
+
+type stripe[K comparable, V any] struct {
+	m       sync.Mutex
+	pending map[K]V
+}
+
+// A Buffered wraps a Cache so that writes are absorbed by a lossy, striped write buffer and applied to
+// the Cache asynchronously by a single goroutine, which takes the Cache's lock once per batch rather
+// than once per write. Repeated writes to the same key made before the buffer is applied are coalesced,
+// so only the last one reaches the Cache. Reads go straight to the Cache and do not observe buffered
+// writes until they have been applied. A Buffered should not be copied, and must be closed with Close
+// to stop its goroutine.
+type Buffered[K comparable, V any] struct {
+	c        *Cache[K, V]
+	seed     maphash.Seed
+	limit    int // maximum number of pending writes per stripe
+	onErr    func(error)
+	stripes  [bufferStripes]stripe[K, V]
+	applying sync.Mutex // held by apply from taking pending writes until they are in the Cache
+	wake     chan struct{}
+	done     chan struct{}
+	closed   chan struct{}
+	once     sync.Once
+}
+
+var _ Cacher[int, int] = (*Buffered[int, int])(nil)
+
+// NewBuffered starts buffering writes to c. Up to size writes to distinct keys may be pending at once;
+// beyond that, writes are dropped. If onErr is non-nil, it is called with any error returned by c's
+// evict func while applying buffered writes. If c was created with WithNoLock, it is locked from then on,
+// since the goroutine applying the writes runs alongside the callers.
+func NewBuffered[K comparable, V any](c *Cache[K, V], size int, onErr func(error)) *Buffered[K, V] {
+	if c.nolock {
+		c.nolock = false
+	}
+	b := &Buffered[K, V]{
+		c:      c,
+		seed:   maphash.MakeSeed(),
+		limit:  max(size/bufferStripes, 1),
+		onErr:  onErr,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		closed: make(chan struct{}),
+	}
+	for i := range b.stripes {
+		b.stripes[i].pending = make(map[K]V)
+	}
+	go b.run()
+	return b
+}
+
+// Get returns the value associated with key in the underlying Cache. Writes that are still buffered are
+// not visible.
+func (b *Buffered[K, V]) Get(key K) (V, bool) {
+	return b.c.Get(key)
+}
+
+// Put buffers a write of val to key. It returns ErrDropped if the buffer is full.
+func (b *Buffered[K, V]) Put(key K, val V) error {
+	s := &b.stripes[maphash.Comparable(b.seed, key)%bufferStripes]
+	s.m.Lock()
+	_, ok := s.pending[key]
+	if !ok && len(s.pending) >= b.limit {
+		s.m.Unlock()
+		return ErrDropped
+	}
+	s.pending[key] = val
+	s.m.Unlock()
+
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Clear discards all buffered writes and clears the underlying Cache.
+func (b *Buffered[K, V]) Clear() error {
+	b.applying.Lock()
+	defer b.applying.Unlock()
+	for i := range b.stripes {
+		s := &b.stripes[i]
+		s.m.Lock()
+		clear(s.pending)
+		s.m.Unlock()
+	}
+	return b.c.Clear()
+}
+
+// Flush applies all buffered writes to the underlying Cache, including those the goroutine is applying,
+// before returning. It returns any errors returned by the evict func.
+func (b *Buffered[K, V]) Flush() error {
+	return b.apply()
+}
+
+// Close flushes any buffered writes and stops the goroutine that applies them. Writes made after Close
+// returns are buffered but only applied by an explicit Flush.
+func (b *Buffered[K, V]) Close() error {
+	b.once.Do(func() { close(b.done) })
+	<-b.closed
+	return b.apply()
+}
+
+func (b *Buffered[K, V]) run() {
+	defer close(b.closed)
+	for {
+		select {
+		case <-b.wake:
+			if err := b.apply(); err != nil && b.onErr != nil {
+				b.onErr(err)
+			}
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// apply moves the pending writes of every stripe into the Cache under a single lock acquisition.
+func (b *Buffered[K, V]) apply() error {
+	b.applying.Lock()
+	defer b.applying.Unlock()
+	var batches [bufferStripes]map[K]V
+	var n int
+	for i := range b.stripes {
+		s := &b.stripes[i]
+		s.m.Lock()
+		if len(s.pending) > 0 {
+			batches[i] = s.pending
+			s.pending = make(map[K]V, len(batches[i]))
+			n++
+		}
+		s.m.Unlock()
+	}
+	if n == 0 {
+		return nil
+	}
+
+	c := b.c
+	c.lock()
+	defer c.unlock()
+	if c.cap == 0 {
+		return nil
+	}
+	if c.evict != nil {
+		c.retry(false)
+	}
+	var err error
+	for _, batch := range batches {
+		for key, val := range batch {
+			err = errors.Join(err, c.put(key, val, c.stamp(c.ttl)))
+		}
+	}
+	return err
+}
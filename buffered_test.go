@@ -0,0 +1,97 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+// stall holds a read lock on c, which must not be empty, until the returned func is called, so that
+// writers queue up behind it.
+func stall[K comparable, V any](c *Cache[K, V]) (release func()) {
+	locked, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		for range c.All() {
+			close(locked)
+			<-done
+			break
+		}
+	}()
+	<-locked
+	return func() { close(done) }
+}
+
+// taken waits until the goroutine of b has taken every pending write.
+func taken[K comparable, V any](b *Buffered[K, V]) {
+	for i := 0; i < bufferStripes; {
+		s := &b.stripes[i]
+		s.m.Lock()
+		n := len(s.pending)
+		s.m.Unlock()
+		if n > 0 {
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		i++
+	}
+}
+
+// TestBufferedFlush checks that Flush waits for writes the goroutine has taken but not yet applied.
+func TestBufferedFlush(t *testing.T) {
+	c := New[int, int](4, nil)
+	c.Put(0, 0)
+	b := NewBuffered(c, 16, nil)
+	defer b.Close()
+
+	release := stall(c)
+	b.Put(1, 1)
+	taken(b)
+	flushed := make(chan error, 1)
+	go func() { flushed <- b.Flush() }()
+	var err error
+	select {
+	case err = <-flushed:
+		t.Error("Flush returned before the write it should wait for was applied")
+		release()
+	case <-time.After(10 * time.Millisecond):
+		release()
+		err = <-flushed
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, ok := b.Get(1); !ok || val != 1 {
+		t.Errorf("Get(1) = %d, %v after Flush, want 1, true", val, ok)
+	}
+}
+
+// TestBufferedClear checks that writes taken by the goroutine before Clear are not applied after it.
+func TestBufferedClear(t *testing.T) {
+	c := New[int, int](4, nil)
+	c.Put(0, 0)
+	b := NewBuffered(c, 16, nil)
+	defer b.Close()
+
+	release := stall(c)
+	b.Put(1, 1)
+	taken(b)
+	cleared := make(chan error)
+	go func() { cleared <- b.Clear() }()
+	time.Sleep(10 * time.Millisecond)
+	release()
+	if err := <-cleared; err != nil {
+		t.Fatal(err)
+	}
+	if val, ok := b.Get(1); ok {
+		t.Errorf("Get(1) = %d after Clear", val)
+	}
+}
+
+// TestBufferedNoLock gives the race detector a Buffered whose Cache was created with WithNoLock.
+func TestBufferedNoLock(t *testing.T) {
+	b := NewBuffered(New[int, int](4, nil, WithNoLock[int, int]()), 16, nil)
+	defer b.Close()
+	for i := range 100 {
+		b.Put(i%8, i)
+		b.Get(i % 8)
+	}
+}
@@ -0,0 +1,24 @@
+package lru
+
+// A Cacher is the set of operations shared by a Cache and any wrapper that decorates one with additional
+// behavior, such as metrics, logging, or fallback to another store.
+type Cacher[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Put(key K, val V) error
+	Clear() error
+}
+
+var _ Cacher[int, int] = (*Cache[int, int])(nil)
+
+// A Middleware wraps a Cacher and returns a Cacher that decorates it.
+type Middleware[K comparable, V any] func(Cacher[K, V]) Cacher[K, V]
+
+// Chain wraps base in each of the given middlewares. The first middleware is the outermost, so it sees
+// each call first and each result last.
+func Chain[K comparable, V any](base Cacher[K, V], mw ...Middleware[K, V]) Cacher[K, V] {
+	c := base
+	for i := len(mw) - 1; i >= 0; i-- {
+		c = mw[i](c)
+	}
+	return c
+}
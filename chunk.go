@@ -0,0 +1,50 @@
+package lru
+
+import "errors"
+
+// WithChunkSize makes Clear, DeleteMatch, and MapValues briefly release the Cache's lock after every n
+// entries they visit, so that a bulk operation on a large Cache, or one whose evict func is slow, does
+// not hold up Get and other callers until it finishes. The price is that these operations are no longer
+// atomic: other callers may observe them half done, and entries written while the lock is released may or
+// may not be affected. In particular, Clear leaves entries that were added after it began in place. A
+// batch evict func set by WithBatchEvict is called once per chunk rather than once per operation.
+func WithChunkSize[K comparable, V any](n int) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.chunk = n
+	}
+}
+
+// pause releases and reacquires the lock if WithChunkSize was set and visited, the number of entries the
+// operation in progress has visited, is a multiple of the chunk size. It first ends the operation's batch,
+// which must be the outermost one, and returns any error from doing so.
+func (c *Cache[K, V]) pause(visited int) error {
+	if c.chunk <= 0 || visited%c.chunk != 0 {
+		return nil
+	}
+	err := c.endBatch()
+	c.unlock()
+	c.lock()
+	c.beginBatch()
+	return err
+}
+
+// removeWhere removes every cached entry that satisfies match, discarding it for reason, and returns the
+// number of entries removed. If chunked is true, it pauses between chunks of entries, and the caller's
+// batch, if any, must be the outermost one.
+func (c *Cache[K, V]) removeWhere(match func(*node[K, V]) bool, reason Reason, chunked bool) (int, error) {
+	var err error
+	var n, visited int
+	// walk backwards so that the node moved into each vacated slot has already been checked; after a
+	// pause, the Cache may have shrunk
+	for i := c.len - 1; i >= 0; i = min(i-1, c.len-1) {
+		if ptr := &c.data[i]; match(ptr) {
+			err = errors.Join(err, c.discard(ptr.key, ptr.val, reason))
+			c.remove(i, false)
+			n++
+		}
+		if visited++; chunked {
+			err = errors.Join(err, c.pause(visited))
+		}
+	}
+	return n, err
+}
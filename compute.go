@@ -0,0 +1,142 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// GetOrCompute returns the cached value associated with key or, if the key is not cached, calls fn to
+// compute it and adds the result to the Cache. fn is called without holding the Cache's lock, and at most
+// once at a time per key: callers that miss while fn is running for the same key wait for its result
+// instead of calling fn themselves. If fn returns an error, nothing is cached, and the error is returned
+// to every waiting caller. The caller that runs fn also receives any error returned by evict as for Put.
+func (c *Cache[K, V]) GetOrCompute(key K, fn func(K) (V, error)) (V, error) {
+	c.lock()
+	if val, ok := c.get(key); ok {
+		c.unlock()
+		return val, nil
+	}
+	f, joined := c.flights.join(key)
+	c.unlock()
+	if joined {
+		return f.Wait()
+	}
+
+	return fly(f, key, fn, func(val V, err error) error {
+		c.lock()
+		defer c.unlock()
+		delete(c.flights, key)
+		if err != nil || c.cap == 0 {
+			return nil
+		}
+		if c.evict != nil {
+			c.retry(false)
+		}
+		return c.put(key, val, c.stamp(c.ttl))
+	})
+}
+
+// GetOrLoadMany returns a map of each key in keys to its value, taking cached values as Get does and
+// loading the rest with a single call to load, which is made without holding the Cache's lock and whose
+// results are added to the Cache. Keys that are already being computed or loaded by another call, such as
+// GetOrCompute or a concurrent GetOrLoadMany, are not passed to load; their results are awaited instead,
+// until ctx is done. Keys for which load returns no value are left out of the map without being cached.
+// If load returns an error, nothing it loaded is cached, and the error is returned along with the values
+// that could be found. If load panics, nothing it loaded is cached either, callers waiting on its keys
+// receive an error, and the panic propagates to the caller. Any errors returned by evict are returned as
+// for Put.
+func (c *Cache[K, V]) GetOrLoadMany(ctx context.Context, keys []K, load func(context.Context, []K) (map[K]V, error)) (map[K]V, error) {
+	found := make(map[K]V, len(keys))
+	seen := make(map[K]struct{}, len(keys))
+	waits := make(map[K]*Future[V])
+	var missed []K
+	c.lock()
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if val, ok := c.get(key); ok {
+			found[key] = val
+			continue
+		}
+		f, joined := c.flights.join(key)
+		if joined {
+			waits[key] = f
+		} else {
+			missed = append(missed, key)
+		}
+	}
+	c.unlock()
+
+	var err error
+	if len(missed) > 0 {
+		var vals map[K]V
+		vals, err = c.loadMany(ctx, missed, load)
+		for _, key := range missed {
+			if val, ok := vals[key]; ok {
+				found[key] = val
+			}
+		}
+	}
+	for key, f := range waits {
+		select {
+		case <-f.Done():
+		case <-ctx.Done():
+			return found, errors.Join(err, ctx.Err())
+		}
+		val, ferr := f.Wait()
+		switch {
+		case ferr == nil:
+			found[key] = val
+		case !errors.Is(ferr, errUnloaded):
+			err = errors.Join(err, ferr)
+		}
+	}
+	return found, err
+}
+
+// errUnloaded resolves the flights of keys for which a call to GetOrLoadMany's load returned no value.
+var errUnloaded = errors.New("lru: key not loaded")
+
+// loadMany calls load for keys, which must have been joined by the caller in c.flights, adds the values
+// it returns to the Cache, and resolves each key's flight. It returns the loaded values, or nil if load
+// fails, along with load's error and any errors returned by evict.
+func (c *Cache[K, V]) loadMany(ctx context.Context, keys []K, load func(context.Context, []K) (map[K]V, error)) (vals map[K]V, err error) {
+	var lerr error
+	panicked := true
+	defer func() {
+		if panicked {
+			lerr = fmt.Errorf("lru: loader for keys %v panicked", keys)
+		}
+		c.lock()
+		defer c.unlock()
+		if lerr == nil && c.cap > 0 && c.evict != nil {
+			c.retry(false)
+		}
+		for _, key := range keys {
+			f := c.flights[key]
+			delete(c.flights, key)
+			val, ok := vals[key]
+			switch {
+			case lerr != nil:
+				f.Resolve(val, lerr)
+			case !ok:
+				f.Resolve(val, errUnloaded)
+			default:
+				if c.cap > 0 {
+					err = errors.Join(err, c.put(key, val, c.stamp(c.ttl)))
+				}
+				f.Resolve(val, nil)
+			}
+		}
+		if lerr != nil {
+			vals = nil
+		}
+		err = errors.Join(lerr, err)
+	}()
+	vals, lerr = load(ctx, keys)
+	panicked = false
+	return vals, nil
+}
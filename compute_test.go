@@ -0,0 +1,28 @@
+package lru
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetOrLoadManyPanic checks that a panic in load reaches the caller and leaves nothing behind.
+func TestGetOrLoadManyPanic(t *testing.T) {
+	c := New[int, int](4, nil)
+	ctx := context.Background()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("GetOrLoadMany did not propagate the panic of load")
+			}
+		}()
+		c.GetOrLoadMany(ctx, []int{1}, func(context.Context, []int) (map[int]int, error) {
+			panic("load failed")
+		})
+	}()
+	found, err := c.GetOrLoadMany(ctx, []int{1}, func(_ context.Context, keys []int) (map[int]int, error) {
+		return map[int]int{1: 1}, nil
+	})
+	if err != nil || found[1] != 1 {
+		t.Errorf("GetOrLoadMany after a panic = %v, %v; want map[1:1], nil", found, err)
+	}
+}
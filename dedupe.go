@@ -0,0 +1,71 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// A Dedupe coalesces concurrent calls that share a key, so that only one of them runs at a time and the
+// others wait for and share its result. Unlike GetOrCompute, it retains nothing once a call returns: the
+// next call for the key runs again. The zero value is ready for use. A Dedupe should not be copied.
+type Dedupe[K comparable, V any] struct {
+	m     sync.Mutex
+	calls flightGroup[K, V]
+}
+
+// Do calls fn for key and returns its result, unless a call for key is already in progress, in which
+// case it waits for that call and returns its result instead. The returned bool reports whether the
+// result was shared with such a call. If fn panics, callers waiting on it receive an error.
+func (d *Dedupe[K, V]) Do(key K, fn func(K) (V, error)) (V, bool, error) {
+	d.m.Lock()
+	f, joined := d.calls.join(key)
+	d.m.Unlock()
+	if joined {
+		val, err := f.Wait()
+		return val, true, err
+	}
+	val, err := fly(f, key, fn, func(V, error) error {
+		d.m.Lock()
+		delete(d.calls, key)
+		d.m.Unlock()
+		return nil
+	})
+	return val, false, err
+}
+
+// A flightGroup tracks the calls in progress for each key. It must be guarded by a lock.
+type flightGroup[K comparable, V any] map[K]*Future[V]
+
+// join returns the Future of the call in progress for key and true, or starts a new call and returns
+// false, in which case the caller must run it with fly.
+func (g *flightGroup[K, V]) join(key K) (*Future[V], bool) {
+	if f, ok := (*g)[key]; ok {
+		return f, true
+	}
+	if *g == nil {
+		*g = make(flightGroup[K, V])
+	}
+	f := NewFuture[V]()
+	(*g)[key] = f
+	return f, false
+}
+
+// fly calls fn for key and resolves f with the result, or with an error if fn panics. Before f is
+// resolved, land is called with the same result; it must remove key from the flightGroup, and may return
+// an error of its own for the caller.
+func fly[K comparable, V any](f *Future[V], key K, fn func(K) (V, error), land func(V, error) error) (val V, err error) {
+	var ferr error
+	panicked := true
+	defer func() {
+		if panicked {
+			ferr = fmt.Errorf("lru: func for key %v panicked", key)
+		}
+		lerr := land(val, ferr)
+		f.Resolve(val, ferr)
+		err = errors.Join(ferr, lerr)
+	}()
+	val, ferr = fn(key)
+	panicked = false
+	return val, nil
+}
@@ -0,0 +1,119 @@
+package lru
+
+import "time"
+
+// A Reason describes why an entry left a Cache.
+type Reason int
+
+const (
+	// Evicted entries made room for new ones.
+	Evicted Reason = iota
+	// Expired entries outlived their time-to-live.
+	Expired
+	// Deleted entries were explicitly removed.
+	Deleted
+	// Cleared entries were removed by Clear.
+	Cleared
+	// Invalid entries were rejected by the func set with WithValidator.
+	Invalid
+)
+
+func (r Reason) String() string {
+	switch r {
+	case Evicted:
+		return "evicted"
+	case Expired:
+		return "expired"
+	case Deleted:
+		return "deleted"
+	case Cleared:
+		return "cleared"
+	case Invalid:
+		return "invalid"
+	}
+	return "unknown"
+}
+
+// WithEvictReason sets an evict func that is also told why each entry left the Cache. It replaces the
+// evict func passed to New.
+func WithEvictReason[K comparable, V any](evict func(K, V, Reason) error) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.evict = evict
+		c.evictLocked = nil
+		c.batch, c.batching = nil, 0
+	}
+}
+
+// WithRecycler hands the value of every entry that leaves the Cache, through eviction, Clear, or
+// deletion, to recycle once the evict func (if any) has been called for it, so that large values such as
+// buffers can be returned to a pool like a sync.Pool. Combined with WithEvictDelay, values are only
+// recycled after the grace period, and with WithEvictRetry, only after evict has succeeded. Values
+// replaced by a Put to the same key are not recycled, since the caller may still be using them.
+func WithRecycler[K comparable, V any](recycle func(V)) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.recycle = recycle
+	}
+}
+
+// discards reports whether entries leaving the Cache need to be passed to discard.
+func (c *Cache[K, V]) discards() bool {
+	return c.evict != nil || c.recycle != nil
+}
+
+// discard reports the removal of an entry that has left the Cache to any watches, then disposes of it.
+func (c *Cache[K, V]) discard(key K, val V, reason Reason) error {
+	if len(c.watches) > 0 {
+		c.notify(WatchRemoved, key, val, reason)
+	}
+	return c.dispose(key, val, reason)
+}
+
+// dispose disposes of an entry that has left the Cache: it calls the evict func, if there is one, then
+// recycles the value. If a grace period was set by WithEvictDelay, both happen only once it has passed,
+// or when the Cache is closed.
+// If evict fails and the entry can be parked on the retry queue, the error is swallowed. Entries pinned by
+// a Handle are only disposed of once it is released.
+func (c *Cache[K, V]) dispose(key K, val V, reason Reason) error {
+	if len(c.pins) > 0 && c.deferDiscard(key, val, reason) {
+		return nil
+	}
+	if !c.discards() {
+		return nil
+	}
+	if c.grace > 0 && !c.closed {
+		c.delay(Entry[K, V]{Key: key, Val: val, Reason: reason})
+		return nil
+	}
+	return c.discardNow(key, val, reason)
+}
+
+func (c *Cache[K, V]) discardNow(key K, val V, reason Reason) error {
+	if c.batching > 0 {
+		c.batched = append(c.batched, Entry[K, V]{Key: key, Val: val, Reason: reason})
+		return nil
+	}
+	if c.workers > 0 && !c.closed && c.dispatch(key, val, reason) {
+		return nil
+	}
+	var err error
+	if c.evict != nil {
+		if err = c.evict(key, val, reason); err != nil && len(c.retries) < c.retrySize {
+			c.retries = append(c.retries, retryEntry[K, V]{
+				key:    key,
+				val:    val,
+				reason: reason,
+				at:     time.Now().Add(c.backoff),
+			})
+			return nil
+		}
+	}
+	// an entry whose evict call failed for good is dropped, so its value can be recycled all the same
+	c.recycleVal(val)
+	return err
+}
+
+func (c *Cache[K, V]) recycleVal(val V) {
+	if c.recycle != nil {
+		c.recycle(val)
+	}
+}
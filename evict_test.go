@@ -0,0 +1,54 @@
+package lru
+
+import "testing"
+
+// TestEvictOverride checks that each option that sets an evict func replaces the one set before it.
+func TestEvictOverride(t *testing.T) {
+	var batched, evicted int
+	batch := WithBatchEvict(func(es []Entry[int, int]) error {
+		batched += len(es)
+		return nil
+	})
+	tests := []struct {
+		name  string
+		evict Option[int, int]
+	}{
+		{"WithEvict", WithEvict(func(int, int) error {
+			evicted++
+			return nil
+		})},
+		{"WithEvictReason", WithEvictReason(func(int, int, Reason) error {
+			evicted++
+			return nil
+		})},
+		{"WithEvictLocked", WithEvictLocked(func(*Locked[int, int], int, int, Reason) error {
+			evicted++
+			return nil
+		})},
+	}
+	for _, tt := range tests {
+		batched, evicted = 0, 0
+		c := NewWithOptions(WithCapacity[int, int](4), batch, tt.evict)
+		c.Put(1, 1)
+		c.Put(2, 2)
+		if err := c.Clear(); err != nil {
+			t.Fatal(err)
+		}
+		if batched != 0 || evicted != 2 {
+			t.Errorf("%s after WithBatchEvict: batch func got %d entries, evict func %d; want 0 and 2",
+				tt.name, batched, evicted)
+		}
+
+		batched, evicted = 0, 0
+		c = NewWithOptions(WithCapacity[int, int](4), tt.evict, batch)
+		c.Put(1, 1)
+		c.Put(2, 2)
+		if err := c.Clear(); err != nil {
+			t.Fatal(err)
+		}
+		if batched != 2 || evicted != 0 {
+			t.Errorf("WithBatchEvict after %s: batch func got %d entries, evict func %d; want 2 and 0",
+				tt.name, batched, evicted)
+		}
+	}
+}
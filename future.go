@@ -0,0 +1,83 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// A Future holds a value that may still be being computed. Caching a *Future rather than the value itself
+// lets the first caller to miss insert a placeholder at once, so that concurrent callers wait for its
+// result instead of computing the value again. A Future should not be copied.
+type Future[V any] struct {
+	once sync.Once
+	done chan struct{}
+	val  V
+	err  error
+}
+
+// NewFuture returns a pending Future.
+func NewFuture[V any]() *Future[V] {
+	return &Future[V]{done: make(chan struct{})}
+}
+
+// Resolve sets the result of the Future and wakes all callers waiting on it. Only the first call to
+// Resolve has any effect.
+func (f *Future[V]) Resolve(val V, err error) {
+	f.once.Do(func() {
+		f.val, f.err = val, err
+		close(f.done)
+	})
+}
+
+// Done returns a channel that is closed once the Future has been resolved.
+func (f *Future[V]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the Future has been resolved and returns its result.
+func (f *Future[V]) Wait() (V, error) {
+	<-f.done
+	return f.val, f.err
+}
+
+// LoadFuture returns the result of the Future cached for key, waiting for it if necessary. If the key is
+// not cached, it caches a pending Future, calls load outside the Cache's lock, and resolves the Future
+// with the result, so that load runs once however many callers miss at the same time. If load fails or
+// panics, the Future is removed from the Cache, without calling the evict func, so that the next caller
+// tries again. The caller that runs load also receives any error returned by evict as for Put.
+func LoadFuture[K comparable, V any](c *Cache[K, *Future[V]], key K, load func(K) (V, error)) (V, error) {
+	f, loaded, err := c.GetOrPutFunc(key, NewFuture[V])
+	if loaded {
+		return f.Wait()
+	}
+
+	var val V
+	var lerr error
+	panicked := true
+	defer func() {
+		if panicked {
+			lerr = fmt.Errorf("lru: loader for key %v panicked", key)
+		}
+		if lerr != nil {
+			removeFuture(c, key, f)
+		}
+		f.Resolve(val, lerr)
+	}()
+	val, lerr = load(key)
+	panicked = false
+	return val, errors.Join(lerr, err)
+}
+
+// removeFuture removes the entry for key if its value is still f.
+func removeFuture[K comparable, V any](c *Cache[K, *Future[V]], key K, f *Future[V]) {
+	c.lock()
+	defer c.unlock()
+	i, ok := c.keys[key]
+	if ok && c.data[i].val == f {
+		c.remove(i, false)
+		if len(c.watches) > 0 {
+			c.notify(WatchRemoved, key, f, Deleted)
+		}
+	}
+}
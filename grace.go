@@ -0,0 +1,57 @@
+package lru
+
+import (
+	"errors"
+	"time"
+)
+
+// WithEvictDelay delays each call to the evict func until d after the entry has been removed from the
+// Cache. The entry stops being visible to Get immediately, but readers that obtained its value just
+// beforehand can keep using it for the duration of the grace period before evict closes or frees it.
+// Because the delayed calls have no caller to report to, their errors are discarded unless WithEvictRetry
+// is also used, in which case failed entries are parked for retry as usual. Close disposes of entries
+// still waiting out the grace period at once, and entries removed after Close are disposed of without
+// delay.
+func WithEvictDelay[K comparable, V any](d time.Duration) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.grace = d
+	}
+}
+
+// A graced entry waits for the grace period set by WithEvictDelay to pass before it is disposed of.
+type graced[K comparable, V any] struct {
+	t *time.Timer
+	e Entry[K, V]
+}
+
+// delay disposes of e once the grace period has passed, unless Close does so first.
+func (c *Cache[K, V]) delay(e Entry[K, V]) {
+	if c.graced == nil {
+		c.graced = make(map[*graced[K, V]]struct{})
+	}
+	g := &graced[K, V]{e: e}
+	c.graced[g] = struct{}{}
+	g.t = time.AfterFunc(c.grace, func() {
+		c.lock()
+		defer c.unlock()
+		if _, ok := c.graced[g]; !ok {
+			// Close got there first
+			return
+		}
+		delete(c.graced, g)
+		c.discardNow(e.Key, e.Val, e.Reason)
+	})
+}
+
+// flushGraced stops the timers of all entries waiting out the grace period and disposes of the entries
+// at once, returning any errors from doing so.
+func (c *Cache[K, V]) flushGraced() error {
+	var err error
+	c.beginBatch()
+	for g := range c.graced {
+		g.t.Stop()
+		delete(c.graced, g)
+		err = errors.Join(err, c.discardNow(g.e.Key, g.e.Val, g.e.Reason))
+	}
+	return errors.Join(err, c.endBatch())
+}
@@ -0,0 +1,140 @@
+package lru
+
+import (
+	"container/heap"
+	"errors"
+	"sync"
+)
+
+type gdEntry[K comparable, V any] struct {
+	key  K
+	val  V
+	pri  float64
+	hits uint64 // only used by LFU
+}
+
+// gdHeap is a min-heap of entries ordered by priority. It keeps keys up to date with each entry's index.
+type gdHeap[K comparable, V any] struct {
+	data []gdEntry[K, V]
+	keys map[K]int
+}
+
+func (h *gdHeap[K, V]) Len() int           { return len(h.data) }
+func (h *gdHeap[K, V]) Less(i, j int) bool { return h.data[i].pri < h.data[j].pri }
+func (h *gdHeap[K, V]) Swap(i, j int) {
+	h.data[i], h.data[j] = h.data[j], h.data[i]
+	h.keys[h.data[i].key] = i
+	h.keys[h.data[j].key] = j
+}
+func (h *gdHeap[K, V]) Push(x any) {
+	e := x.(gdEntry[K, V])
+	h.keys[e.key] = len(h.data)
+	h.data = append(h.data, e)
+}
+func (h *gdHeap[K, V]) Pop() any {
+	e := h.data[len(h.data)-1]
+	h.data[len(h.data)-1] = gdEntry[K, V]{}
+	h.data = h.data[:len(h.data)-1]
+	delete(h.keys, e.key)
+	return e
+}
+
+// A GreedyDual is a generic, concurrency-safe cache that uses the GreedyDual eviction policy, which weighs
+// recency against the cost of recomputing each entry. Each entry is assigned a priority equal to its cost
+// plus an inflation value that rises every time an entry is evicted; the entry with the lowest priority is
+// evicted first. Cheap entries therefore age out quickly, while expensive entries survive as long as
+// their cost exceeds the priority gained by more recently used ones. A GreedyDual should not be copied.
+type GreedyDual[K comparable, V any] struct {
+	m     sync.Mutex
+	cap   uint64
+	infl  float64
+	cost  func(K, V) float64
+	evict func(K, V) error
+	h     gdHeap[K, V]
+}
+
+var _ Cacher[int, int] = (*GreedyDual[int, int])(nil)
+
+// NewGreedyDual creates a new GreedyDual with a capacity of cap items. cost returns the cost of
+// recomputing an entry and must be non-negative. If evict is non-nil, it is called each time a key-value
+// pair is evicted.
+func NewGreedyDual[K comparable, V any](cap uint64, cost func(K, V) float64, evict func(K, V) error) *GreedyDual[K, V] {
+	return &GreedyDual[K, V]{
+		cap:   cap,
+		cost:  cost,
+		evict: evict,
+		h: gdHeap[K, V]{
+			data: make([]gdEntry[K, V], 0, cap),
+			keys: make(map[K]int, cap),
+		},
+	}
+}
+
+// Get returns the cached value associated with key and a bool, which is true if the key was found
+// and false otherwise. A hit restores the entry's priority to its full cost above the current inflation.
+func (g *GreedyDual[K, V]) Get(key K) (V, bool) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	i, ok := g.h.keys[key]
+	if !ok {
+		return *new(V), false
+	}
+	e := &g.h.data[i]
+	e.pri = g.infl + g.cost(e.key, e.val)
+	val := e.val
+	heap.Fix(&g.h, i)
+	return val, true
+}
+
+// Put adds a key-value pair to the GreedyDual. If the GreedyDual is full and the key is not already
+// cached, it evicts the entry with the lowest priority. If an eviction occurs and the evict func is
+// non-nil, Put returns any error returned by evict. Otherwise, the returned error will be nil.
+func (g *GreedyDual[K, V]) Put(key K, val V) error {
+	g.m.Lock()
+	defer g.m.Unlock()
+	var err error
+
+	if g.cap == 0 {
+		return err
+	}
+
+	pri := g.infl + g.cost(key, val)
+	if i, ok := g.h.keys[key]; ok {
+		g.h.data[i].val = val
+		g.h.data[i].pri = pri
+		heap.Fix(&g.h, i)
+		return err
+	}
+
+	if uint64(g.h.Len()) >= g.cap {
+		victim := heap.Pop(&g.h).(gdEntry[K, V])
+		g.infl = victim.pri
+		if g.evict != nil {
+			err = g.evict(victim.key, victim.val)
+		}
+		// the new entry's priority must account for the raised inflation
+		pri = g.infl + g.cost(key, val)
+	}
+
+	heap.Push(&g.h, gdEntry[K, V]{key: key, val: val, pri: pri})
+	return err
+}
+
+// Clear evicts all entries from the GreedyDual (calling the evict func if it exists) and resets it.
+// A cleared GreedyDual is safe for re-use.
+func (g *GreedyDual[K, V]) Clear() error {
+	g.m.Lock()
+	defer g.m.Unlock()
+	var err error
+
+	if g.evict != nil {
+		for _, e := range g.h.data {
+			err = errors.Join(err, g.evict(e.key, e.val))
+		}
+	}
+	clear(g.h.data)
+	g.h.data = g.h.data[:0]
+	clear(g.h.keys)
+	g.infl = 0
+	return err
+}
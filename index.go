@@ -0,0 +1,131 @@
+package lru
+
+import (
+	"errors"
+	"iter"
+)
+
+// indexer is implemented by the secondary indexes of a Cache, which are kept up to date under its lock.
+type indexer[K comparable, V any] interface {
+	insert(key K, val V)
+	delete(key K, val V)
+	reset()
+}
+
+// An Index is a secondary index of a Cache that groups its entries by a second key derived from their
+// values, such as the id of the user a cached session belongs to, so that they can be found or deleted
+// together. The Cache keeps the Index up to date as entries are written and leave it.
+type Index[K comparable, V any, K2 comparable] struct {
+	c    *Cache[K, V]
+	key  func(V) K2
+	keys map[K2]map[K]struct{}
+}
+
+// NewIndex adds an Index to c that groups its entries by the second key that key returns for their values,
+// including the entries already cached. key is called while the Cache is locked, so it must not call
+// methods on the Cache.
+func NewIndex[K comparable, V any, K2 comparable](c *Cache[K, V], key func(V) K2) *Index[K, V, K2] {
+	x := &Index[K, V, K2]{c: c, key: key, keys: make(map[K2]map[K]struct{})}
+	c.lock()
+	defer c.unlock()
+	for i := range c.data[:c.len] {
+		x.insert(c.data[i].key, c.data[i].val)
+	}
+	c.indexes = append(c.indexes, x)
+	return x
+}
+
+// Get returns an iter.Seq2 that iterates over the unexpired entries whose second key is k2, without
+// updating their recency. Like Cache.All, it holds a read lock on the Cache while iterating.
+func (x *Index[K, V, K2]) Get(k2 K2) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		c := x.c
+		c.rlock()
+		defer c.runlock()
+		live := c.live()
+		for key := range x.keys[k2] {
+			n := &c.data[c.keys[key]]
+			if live.ok(n) && !yield(n.key, n.val) {
+				return
+			}
+		}
+	}
+}
+
+// Delete removes every entry whose second key is k2 from the Cache, calling the evict func (if it exists)
+// with the Deleted reason for each. It returns the number of entries removed and any errors returned by
+// evict.
+func (x *Index[K, V, K2]) Delete(k2 K2) (int, error) {
+	c := x.c
+	c.lock()
+	defer c.unlock()
+	return x.drop(k2)
+}
+
+// drop implements Delete for a caller holding the Cache's write lock.
+func (x *Index[K, V, K2]) drop(k2 K2) (int, error) {
+	c := x.c
+	var err error
+	var n int
+	c.beginBatch()
+	// removing an entry updates the set being iterated over, which is allowed for the entry just visited
+	for key := range x.keys[k2] {
+		i := c.keys[key]
+		ptr := c.data[i]
+		c.remove(i, false)
+		err = errors.Join(err, c.discard(ptr.key, ptr.val, Deleted))
+		n++
+	}
+	return n, errors.Join(err, c.endBatch())
+}
+
+// Detach removes the Index from its Cache, which stops keeping it up to date.
+func (x *Index[K, V, K2]) Detach() {
+	c := x.c
+	c.lock()
+	defer c.unlock()
+	for i, y := range c.indexes {
+		if y == indexer[K, V](x) {
+			c.indexes = append(c.indexes[:i], c.indexes[i+1:]...)
+			break
+		}
+	}
+	clear(x.keys)
+}
+
+func (x *Index[K, V, K2]) insert(key K, val V) {
+	k2 := x.key(val)
+	set := x.keys[k2]
+	if set == nil {
+		set = make(map[K]struct{})
+		x.keys[k2] = set
+	}
+	set[key] = struct{}{}
+}
+
+func (x *Index[K, V, K2]) delete(key K, val V) {
+	k2 := x.key(val)
+	set := x.keys[k2]
+	delete(set, key)
+	if len(set) == 0 {
+		delete(x.keys, k2)
+	}
+}
+
+func (x *Index[K, V, K2]) reset() {
+	clear(x.keys)
+}
+
+// index adds the entry for key to every Index of the Cache.
+func (c *Cache[K, V]) index(key K, val V) {
+	for _, x := range c.indexes {
+		x.insert(key, val)
+	}
+}
+
+// unindex removes the entry for key from every Index of the Cache.
+func (c *Cache[K, V]) unindex(key K, val V) {
+	for _, x := range c.indexes {
+		x.delete(key, val)
+	}
+}
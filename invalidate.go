@@ -0,0 +1,180 @@
+package lru
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// invalidationBatch is the maximum number of records an Invalidator applies under a single lock
+// acquisition.
+const invalidationBatch = 64
+
+// ErrNoPrefix is reported for an Invalidation with a Prefix applied to a Cache whose keys are not strings.
+var ErrNoPrefix = errors.New("lru: prefix invalidation requires string keys")
+
+// ErrNoTags is reported for an Invalidation with a Tag applied by an Invalidator without an Index.
+var ErrNoTags = errors.New("lru: tag invalidation requires an index")
+
+// An Invalidation is a record of a change feed, such as one produced by change data capture, that names
+// the cached entries that are no longer valid. Every non-zero field selects entries to remove.
+type Invalidation[K comparable] struct {
+	Keys   []K       `json:",omitempty"` // the keys of the entries
+	Prefix string    `json:",omitempty"` // a prefix of the keys of the entries, for Caches with string keys
+	Tag    string    `json:",omitempty"` // the second key of the entries in the Invalidator's Index
+	Before time.Time `json:",omitzero"`  // entries last written before this time; see Cache.ExpireBefore
+}
+
+// InvalidatorStats counts the work done by an Invalidator.
+type InvalidatorStats struct {
+	Records uint64 // records applied
+	Removed uint64 // entries removed from the Cache
+	Errors  uint64 // records that could not be applied in full
+}
+
+// An Invalidator applies Invalidations from a change feed to a Cache, in batches that each take the
+// Cache's lock once. Entries it removes are discarded with the Deleted reason, except those selected by
+// Before, which are discarded with the Expired reason. An Invalidator is safe for concurrent use.
+type Invalidator[K comparable, V any] struct {
+	c     *Cache[K, V]
+	tags  *Index[K, V, string]
+	onErr func(error)
+
+	records atomic.Uint64
+	removed atomic.Uint64
+	errors  atomic.Uint64
+}
+
+// NewInvalidator creates a new Invalidator for c. If tags is non-nil, it is the Index of c by which
+// Invalidations with a Tag select entries. If onErr is non-nil, it is called by Consume and ConsumeJSON
+// with the errors of each batch, including those returned by c's evict func.
+func NewInvalidator[K comparable, V any](c *Cache[K, V], tags *Index[K, V, string], onErr func(error)) *Invalidator[K, V] {
+	return &Invalidator[K, V]{c: c, tags: tags, onErr: onErr}
+}
+
+// Apply applies recs to the Cache under a single lock acquisition. It returns the number of entries
+// removed and any errors, which do not stop the remaining records from being applied.
+func (v *Invalidator[K, V]) Apply(recs ...Invalidation[K]) (int, error) {
+	c := v.c
+	c.lock()
+	defer c.unlock()
+	var err error
+	var removed int
+	c.beginBatch()
+	for _, rec := range recs {
+		n, rerr := v.apply(rec)
+		removed += n
+		if rerr != nil {
+			v.errors.Add(1)
+			err = errors.Join(err, rerr)
+		}
+	}
+	err = errors.Join(err, c.endBatch())
+	v.records.Add(uint64(len(recs)))
+	v.removed.Add(uint64(removed))
+	return removed, err
+}
+
+func (v *Invalidator[K, V]) apply(rec Invalidation[K]) (int, error) {
+	c := v.c
+	var err error
+	var removed int
+	for _, key := range rec.Keys {
+		_, ok, kerr := c.delete(key)
+		if ok {
+			removed++
+		}
+		err = errors.Join(err, kerr)
+	}
+	if rec.Prefix != "" {
+		var zero K
+		if _, ok := any(zero).(string); !ok {
+			err = errors.Join(err, ErrNoPrefix)
+		} else {
+			n, perr := c.deleteWhere(func(key K) bool {
+				return strings.HasPrefix(any(key).(string), rec.Prefix)
+			}, false)
+			removed += n
+			err = errors.Join(err, perr)
+		}
+	}
+	if rec.Tag != "" {
+		if v.tags == nil {
+			err = errors.Join(err, ErrNoTags)
+		} else {
+			n, terr := v.tags.drop(rec.Tag)
+			removed += n
+			err = errors.Join(err, terr)
+		}
+	}
+	if !rec.Before.IsZero() {
+		n, berr := c.expireBefore(rec.Before)
+		removed += n
+		err = errors.Join(err, berr)
+	}
+	return removed, err
+}
+
+// Consume applies the Invalidations received from ch until it is closed. Records that arrive while a
+// batch is being applied are gathered into the next batch.
+func (v *Invalidator[K, V]) Consume(ch <-chan Invalidation[K]) {
+	batch := make([]Invalidation[K], 0, invalidationBatch)
+	for rec := range ch {
+		batch = append(batch[:0], rec)
+	gather:
+		for len(batch) < invalidationBatch {
+			select {
+			case rec, ok := <-ch:
+				if !ok {
+					break gather
+				}
+				batch = append(batch, rec)
+			default:
+				break gather
+			}
+		}
+		if _, err := v.Apply(batch...); err != nil && v.onErr != nil {
+			v.onErr(err)
+		}
+		clear(batch)
+	}
+}
+
+// ConsumeJSON applies the Invalidations decoded from r, a stream of JSON objects, until it reaches the
+// end of the stream. It returns nil at the end of the stream, or the first error encountered while
+// decoding it; records decoded before the error are still applied.
+func (v *Invalidator[K, V]) ConsumeJSON(r io.Reader) error {
+	ch := make(chan Invalidation[K], invalidationBatch)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		v.Consume(ch)
+	}()
+	dec := json.NewDecoder(r)
+	var err error
+	for {
+		var rec Invalidation[K]
+		if err = dec.Decode(&rec); err != nil {
+			break
+		}
+		ch <- rec
+	}
+	close(ch)
+	<-done
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// Stats returns the Invalidator's counts so far.
+func (v *Invalidator[K, V]) Stats() InvalidatorStats {
+	return InvalidatorStats{
+		Records: v.records.Load(),
+		Removed: v.removed.Load(),
+		Errors:  v.errors.Load(),
+	}
+}
@@ -0,0 +1,107 @@
+package lru
+
+import (
+	"container/heap"
+	"errors"
+	"sync"
+)
+
+// An LFU is a generic, concurrency-safe least-frequently used cache with dynamic aging (LFUDA). Each
+// entry's priority is its access count plus an age value that rises to the priority of every evicted
+// entry, and the entry with the lowest priority is evicted first. Aging lets newly popular entries
+// overtake formerly popular ones whose counts were accumulated long ago, which a plain LFU never forgets.
+// An LFU should not be copied.
+type LFU[K comparable, V any] struct {
+	m     sync.Mutex
+	cap   uint64
+	age   float64
+	evict func(K, V) error
+	h     gdHeap[K, V]
+}
+
+var _ Cacher[int, int] = (*LFU[int, int])(nil)
+
+// NewLFU creates a new LFU with a capacity of cap items. If evict is non-nil, it is called each time a
+// key-value pair is evicted.
+func NewLFU[K comparable, V any](cap uint64, evict func(K, V) error) *LFU[K, V] {
+	return &LFU[K, V]{
+		cap:   cap,
+		evict: evict,
+		h: gdHeap[K, V]{
+			data: make([]gdEntry[K, V], 0, cap),
+			keys: make(map[K]int, cap),
+		},
+	}
+}
+
+// access counts an access of the entry at index i and restores its priority.
+func (l *LFU[K, V]) access(i int) {
+	e := &l.h.data[i]
+	e.hits++
+	e.pri = l.age + float64(e.hits)
+	heap.Fix(&l.h, i)
+}
+
+// Get returns the cached value associated with key and a bool, which is true if the key was found
+// and false otherwise.
+func (l *LFU[K, V]) Get(key K) (V, bool) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	i, ok := l.h.keys[key]
+	if !ok {
+		return *new(V), false
+	}
+	val := l.h.data[i].val
+	l.access(i)
+	return val, true
+}
+
+// Put adds a key-value pair to the LFU, counting as an access if the key is already cached. If the LFU is
+// full and the key is not already cached, it evicts the entry with the lowest priority. If an eviction
+// occurs and the evict func is non-nil, Put returns any error returned by evict. Otherwise, the returned
+// error will be nil.
+func (l *LFU[K, V]) Put(key K, val V) error {
+	l.m.Lock()
+	defer l.m.Unlock()
+	var err error
+
+	if l.cap == 0 {
+		return err
+	}
+
+	if i, ok := l.h.keys[key]; ok {
+		l.h.data[i].val = val
+		l.access(i)
+		return err
+	}
+
+	if uint64(l.h.Len()) >= l.cap {
+		victim := heap.Pop(&l.h).(gdEntry[K, V])
+		l.age = victim.pri
+		if l.evict != nil {
+			err = l.evict(victim.key, victim.val)
+		}
+	}
+
+	heap.Push(&l.h, gdEntry[K, V]{key: key, val: val, pri: l.age + 1, hits: 1})
+	return err
+}
+
+// Clear evicts all entries from the LFU (calling the evict func if it exists) and resets it.
+// A cleared LFU is safe for re-use.
+func (l *LFU[K, V]) Clear() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+	var err error
+
+	if l.evict != nil {
+		for _, e := range l.h.data {
+			err = errors.Join(err, l.evict(e.key, e.val))
+		}
+	}
+	clear(l.h.data)
+	l.h.data = l.h.data[:0]
+	clear(l.h.keys)
+	l.age = 0
+	return err
+}
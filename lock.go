@@ -0,0 +1,86 @@
+package lru
+
+// WithNoLock disables the Cache's mutex for callers that already confine the Cache to a single goroutine
+// or guard it with a lock of their own. Such a Cache is not safe for concurrent use, including
+// concurrent iteration, but is otherwise identical to any other. The option is ignored when combined with
+// options that change the Cache from goroutines or timers of its own, namely WithJanitor,
+// WithBackgroundEviction, WithAsyncEvict, and WithEvictDelay, since a lock held by the caller cannot
+// guard against those. For the same reason, it stops having effect once the Cache is passed to
+// NewBuffered.
+func WithNoLock[K comparable, V any]() Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.nolock = true
+	}
+}
+
+// lock acquires the Cache's mutex. In builds with the lrudebug tag, it panics instead of deadlocking
+// if the calling goroutine already holds the mutex.
+func (c *Cache[K, V]) lock() {
+	if c.nolock {
+		return
+	}
+	c.owner.check()
+	c.m.Lock()
+	c.owner.set()
+}
+
+// unlock releases the Cache's mutex, then runs the funcs passed to Locked.Defer while it was held.
+func (c *Cache[K, V]) unlock() {
+	after := c.after
+	if after != nil {
+		c.after = nil
+	}
+	if !c.nolock {
+		c.owner.unset()
+		c.m.Unlock()
+	}
+	for _, fn := range after {
+		fn(c)
+	}
+}
+
+// rlock acquires the Cache's mutex for reading. Operations that neither mutate the Cache nor change its
+// recency order may share it.
+func (c *Cache[K, V]) rlock() {
+	if c.nolock {
+		return
+	}
+	c.owner.check()
+	c.m.RLock()
+	c.owner.set()
+}
+
+// runlock releases a read lock acquired by rlock.
+func (c *Cache[K, V]) runlock() {
+	if c.nolock {
+		return
+	}
+	c.owner.unset()
+	c.m.RUnlock()
+}
+
+// trylock acquires the Cache's mutex if it is free and reports whether it did.
+func (c *Cache[K, V]) trylock() bool {
+	if c.nolock {
+		return true
+	}
+	c.owner.check()
+	if !c.m.TryLock() {
+		return false
+	}
+	c.owner.set()
+	return true
+}
+
+// tryrlock acquires the Cache's mutex for reading if no writer holds it and reports whether it did.
+func (c *Cache[K, V]) tryrlock() bool {
+	if c.nolock {
+		return true
+	}
+	c.owner.check()
+	if !c.m.TryRLock() {
+		return false
+	}
+	c.owner.set()
+	return true
+}
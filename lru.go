@@ -1,9 +1,14 @@
 package lru
 
 import (
+	"cmp"
 	"errors"
+	"hash/maphash"
 	"iter"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type node[K comparable, V any] struct {
@@ -11,34 +16,201 @@ type node[K comparable, V any] struct {
 	last int
 	key  K
 	val  V
+	tick uint64 // value of the Cache's clock at the last access
+	hits uint64 // number of accesses since insertion
+	ver  uint64 // value of the Cache's version counter at the last write
+	ins  uint64 // value of the Cache's version counter at insertion; see Inserted
+	exp  int64  // expiration time relative to the Cache's epoch, or 0 if the node does not expire
+	at   int64  // time of the last write relative to the Cache's epoch, or 0 if not recorded; see WithWriteTimes
+	born int64  // time of insertion relative to the Cache's epoch, or 0 if not recorded; see Ages
+	used int64  // time of the last access relative to the Cache's epoch, if recorded; see WithAccessTimes
+	wt   uint64 // see WithWeigher
+	ro   bool   // see SetReadOnly
+	old  bool   // whether the node is behind the midpoint; see WithMidpointInsertion
 }
 
 // A Cache is a generic, concurrency-safe least-recently used (LRU) cache. A Cache should not be copied.
+//
+// Cache hits in Get and Put, and loops over the Cache's iterators other than Inserted, do not allocate.
+//
+// The evict func and the bodies of loops over a Cache's iterators run while the Cache is locked, so they
+// must not call methods on the same Cache; an evict func set with WithEvictLocked may defer such calls
+// instead. Building with the lrudebug tag turns such calls into panics rather than deadlocks.
 type Cache[K comparable, V any] struct {
-	m     sync.Mutex
+	m     sync.RWMutex
+	owner owner
 	len   int
 	head  int
 	tail  int
 	cap   uint64
-	evict func(K, V) error
+	evict func(K, V, Reason) error
 	data  []node[K, V]
 	keys  map[K]int
+
+	nolock  bool
+	sampled bool
+	clock   uint64 // incremented on every access
+	version uint64 // incremented on every write
+	ttl     time.Duration
+	maxAge  time.Duration
+	epoch   time.Time // creation time; see now
+
+	validate  func(K, V) bool                // see WithValidator
+	admit     func(K, V, AdmissionInfo) bool // see WithAdmission
+	untouched bool                           // see WithUntouchedWrites
+	accessed  bool                           // see WithAccessTimes
+	stamped   bool                           // whether every write records its time; see WithWriteTimes
+	chunk     int                            // see WithChunkSize
+	freq      *sketch[K]                     // see WithTinyLFU
+
+	shadows []*Shadow[K]
+	every   uint64 // sampling rate for observed requests
+	seed    maphash.Seed
+
+	score    func(K, V, EntryInfo) float64
+	samples  int
+	canEvict func(K, V) bool
+	attempts int
+
+	retries   []retryEntry[K, V]
+	retrySize int
+	backoff   time.Duration
+	grace     time.Duration
+	graced    map[*graced[K, V]]struct{} // entries waiting out the grace period
+	recycle   func(V)
+
+	evictLocked func(*Locked[K, V], K, V, Reason) error // see WithEvictLocked
+	after       []func(*Cache[K, V])                    // see Locked.Defer
+	watches     []*watch[K, V]                          // see Watch
+
+	flights flightGroup[K, V] // computations in progress; see GetOrCompute
+	pins    map[K]*pin[V]     // see Acquire
+	indexes []indexer[K, V]   // see NewIndex
+	origins map[K]string      // see WithOrigins
+	origin  string            // origin of the write in progress, if given by the caller
+
+	weigh     func(K, V) uint64 // see WithWeigher
+	maxWeight uint64
+	weight    uint64
+	spare     K    // key of the entry fit is making room for, which must not be evicted
+	sparing   bool // whether spare is set
+
+	hits       atomic.Uint64 // see Stats
+	misses     atomic.Uint64
+	evictions  atomic.Uint64
+	insertions atomic.Uint64
+	changes    atomic.Uint64 // see Version
+	expelled   uint64        // entries evicted so far, which unlike the statistics is never reset
+
+	batch    func([]Entry[K, V]) error // see WithBatchEvict
+	batching int
+	batched  []Entry[K, V]
+
+	quarantined []qEntry[K, V]
+	qsize       int
+	qwindow     time.Duration
+	rescues     uint64
+
+	tenant  func(K) string
+	quota   func(string) int
+	tenants map[string]*TenantStats
+
+	midpoint bool // see WithMidpointInsertion
+	slru     bool // see WithSegmentedLRU
+	oldFrac  float64
+	mid      int // index of the first old node, if oldLen > 0
+	oldLen   int
+
+	high    uint64 // see WithWatermarks
+	low     uint64
+	wake    chan struct{} // wakes the background evictor
+	pending bool          // whether the background evictor has been woken but not finished
+	janitor time.Duration // interval between sweeps for expired entries
+	onErr   func(error)   // receives errors from background goroutines
+
+	workers  int        // see WithAsyncEvict
+	evictCap int        // maximum length of evictq, or 0 if it is unbounded
+	queued   sync.Mutex // guards the fields below
+	evictq   []Entry[K, V]
+	ready    *sync.Cond // signaled when evictq grows or draining is set
+	idle     *sync.Cond // signaled when inflight drops to 0
+	inflight int        // entries dispatched but not yet disposed of
+	draining bool
+
+	stop     chan struct{} // closed by Close to stop background goroutines
+	closed   bool          // set by Close under the lock
+	stopOnce sync.Once
+	wg       sync.WaitGroup
 }
 
+// An Option configures optional behavior of a Cache.
+type Option[K comparable, V any] func(*Cache[K, V])
+
 // New creates a new Cache with a capacity of cap items. If evict is non-nil, it is called each time a key-value
-// pair is evicted.
-func New[K comparable, V any](cap uint64, evict func(K, V) error) *Cache[K, V] {
-	return &Cache[K, V]{
-		cap:   cap,
-		keys:  make(map[K]int, cap),
-		data:  make([]node[K, V], cap),
-		evict: evict,
+// pair is evicted. Any opts are applied in order.
+func New[K comparable, V any](cap uint64, evict func(K, V) error, opts ...Option[K, V]) *Cache[K, V] {
+	return NewWithOptions(append([]Option[K, V]{WithCapacity[K, V](cap), WithEvict(evict)}, opts...)...)
+}
+
+// NewWithOptions creates a new Cache configured entirely by opts, which are applied in order. Without
+// WithCapacity, the Cache has a capacity of 0 and caches nothing.
+func NewWithOptions[K comparable, V any](opts ...Option[K, V]) *Cache[K, V] {
+	c := &Cache[K, V]{epoch: time.Now()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.ttl > 0 || c.maxAge > 0 || c.accessed {
+		// writes read the clock anyway
+		c.stamped = true
+	}
+	if c.wake != nil || c.janitor > 0 || c.workers > 0 || c.grace > 0 {
+		// the Cache changes itself in the background, which only its own lock can guard against
+		c.nolock = false
+	}
+	c.keys = make(map[K]int, c.cap)
+	c.data = make([]node[K, V], c.cap)
+	c.start()
+	return c
+}
+
+// WithCapacity sets the capacity of the Cache to cap items.
+func WithCapacity[K comparable, V any](cap uint64) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.cap = cap
+	}
+}
+
+// WithEvict sets a func that is called each time a key-value pair is evicted, replacing any evict func set
+// before it. A nil evict removes it.
+func WithEvict[K comparable, V any](evict func(K, V) error) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.evictLocked = nil
+		c.batch, c.batching = nil, 0
+		if evict == nil {
+			c.evict = nil
+			return
+		}
+		c.evict = func(key K, val V, _ Reason) error { return evict(key, val) }
+	}
+}
+
+// WithUntouchedWrites makes writes to keys that are already cached replace the value without counting as
+// an access, so that, for instance, a background job refreshing values does not keep them from being
+// evicted. The entry keeps its place in the recency order and its hit count. Writes of new keys are
+// unaffected.
+func WithUntouchedWrites[K comparable, V any]() Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.untouched = true
 	}
 }
 
 // promote moves the node at index i to the front of the queue.
 func (c *Cache[K, V]) promote(i int) {
 	ptr := &c.data[i]
+	if c.midpoint {
+		defer c.rebalance()
+		c.leaveOld(i)
+	}
 
 	if i == c.head {
 		return
@@ -56,13 +228,136 @@ func (c *Cache[K, V]) promote(i int) {
 	c.head = i
 }
 
+// expel disposes of the node at index i, which is being evicted to make room for another, by moving it
+// into quarantine or discarding it. It does not remove the node.
+func (c *Cache[K, V]) expel(i int) error {
+	ptr := &c.data[i]
+	c.evictions.Add(1)
+	c.expelled++
+	if c.qsize > 0 {
+		if len(c.watches) > 0 {
+			c.notify(WatchRemoved, ptr.key, ptr.val, Evicted)
+		}
+		return c.quarantine(ptr.key, ptr.val, ptr.exp, ptr.at)
+	}
+	return c.discard(ptr.key, ptr.val, Evicted)
+}
+
+// touch records an access of the node at index i.
+func (c *Cache[K, V]) touch(i int) {
+	c.clock++
+	c.data[i].tick = c.clock
+	c.data[i].hits++
+	if c.accessed {
+		c.data[i].used = c.now()
+	}
+	if len(c.watches) > 0 {
+		c.notify(WatchPromoted, c.data[i].key, c.data[i].val, 0)
+	}
+}
+
+// remove deletes the node at index i from the Cache, counting it as an eviction if evicted is true. To
+// keep the occupied nodes contiguous, the node at the highest occupied index is moved into slot i.
+func (c *Cache[K, V]) remove(i int, evicted bool) {
+	ptr := &c.data[i]
+	c.changes.Add(1)
+	delete(c.keys, ptr.key)
+	c.untrack(ptr.key, evicted)
+	c.weight -= ptr.wt
+	c.unindex(ptr.key, ptr.val)
+	if c.origins != nil {
+		delete(c.origins, ptr.key)
+	}
+	if c.midpoint {
+		defer c.rebalance()
+		c.leaveOld(i)
+	}
+
+	if c.len == 1 {
+		clear(c.data[:1])
+		c.head, c.tail, c.len = 0, 0, 0
+		return
+	}
+
+	// unlink i
+	if i == c.head {
+		c.head = ptr.next
+	} else {
+		c.data[ptr.last].next = ptr.next
+	}
+	if i == c.tail {
+		c.tail = ptr.last
+	} else {
+		c.data[ptr.next].last = ptr.last
+	}
+
+	// fill the hole with the last node
+	j := c.len - 1
+	if i != j {
+		*ptr = c.data[j]
+		if j == c.head {
+			c.head = i
+		} else {
+			c.data[ptr.last].next = i
+		}
+		if j == c.tail {
+			c.tail = i
+		} else {
+			c.data[ptr.next].last = i
+		}
+		c.keys[ptr.key] = i
+		if c.oldLen > 0 && c.mid == j {
+			c.mid = i
+		}
+	}
+	c.data[j] = node[K, V]{}
+	c.len--
+}
+
+// lookup returns the index of the node for key, removing it if it has expired or is invalid or rescuing
+// it from quarantine if necessary, and reports the request to any attached shadows.
+func (c *Cache[K, V]) lookup(key K) (int, bool) {
+	if c.freq != nil {
+		c.freq.add(key, c.cap)
+	}
+	i, ok, _ := c.find(key)
+	if !ok && len(c.quarantined) > 0 {
+		i, ok = c.rescue(key)
+	}
+	if ok && c.validate != nil && !c.validate(key, c.data[i].val) {
+		n := c.data[i]
+		c.remove(i, false)
+		c.discard(n.key, n.val, Invalid)
+		ok = false
+	}
+	c.count(ok)
+	if c.observed(key) {
+		for _, s := range c.shadows {
+			s.observe(key, ok)
+		}
+		c.request(key, ok)
+	}
+	return i, ok
+}
+
 // Get returns the cached value associated with key and a bool, which is true if the key was found
-// and false otherwise.
+// and false otherwise. An expired entry is not found; instead, it is removed from the Cache.
 func (c *Cache[K, V]) Get(key K) (V, bool) {
-	c.m.Lock()
-	defer c.m.Unlock()
-	i, ok := c.keys[key]
+	if c.sampled {
+		if val, ok, done := c.getShared(key); done {
+			return val, ok
+		}
+	}
+	c.lock()
+	defer c.unlock()
+	return c.get(key)
+}
+
+// get implements Get for a caller holding the write lock.
+func (c *Cache[K, V]) get(key K) (V, bool) {
+	i, ok := c.lookup(key)
 	if ok {
+		c.touch(i)
 		val := c.data[i].val
 		c.promote(i)
 		return val, true
@@ -71,113 +366,629 @@ func (c *Cache[K, V]) Get(key K) (V, bool) {
 	return *new(V), false
 }
 
+// Peek returns the cached value associated with key and a bool, which is true if the key was found and
+// false otherwise, without updating the entry's recency or counting the request towards any statistics.
+// Peek only takes a read lock, so an expired entry is reported as missing but not removed.
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	c.rlock()
+	defer c.runlock()
+	return c.peek(key)
+}
+
+// peek implements Peek for a caller holding the lock.
+func (c *Cache[K, V]) peek(key K) (V, bool) {
+	i, ok := c.keys[key]
+	if !ok || c.expired(i) {
+		return *new(V), false
+	}
+	return c.data[i].val, true
+}
+
+// Contains reports whether key is cached, without updating the entry's recency, counting the request
+// towards any statistics, or copying its value. Like Peek, it only takes a read lock, so an expired
+// entry is reported as missing but not removed.
+func (c *Cache[K, V]) Contains(key K) bool {
+	c.rlock()
+	defer c.runlock()
+	return c.contains(key)
+}
+
+// contains implements Contains for a caller holding the lock.
+func (c *Cache[K, V]) contains(key K) bool {
+	i, ok := c.keys[key]
+	return ok && !c.expired(i)
+}
+
 // Put adds a key-value pair to the Cache. If the Cache is full and the key is not already cached, it
 // evicts the least-recently used entry. If an eviction occurs and the Cache's evict func is non-nil,
-// Put returns any error returned by evict. Otherwise, the returned error will be nil.
+// Put returns any error returned by evict. If no entry may be evicted (see WithCanEvict), Put returns
+// ErrNoVictim, if the cached entry is read-only (see SetReadOnly), Put returns ErrReadOnly, and if the
+// entry is heavier than the maximum weight (see WithMaxWeight), Put returns ErrTooHeavy. Otherwise, the
+// returned error will be nil.
 func (c *Cache[K, V]) Put(key K, val V) error {
-	c.m.Lock()
-	defer c.m.Unlock()
+	c.lock()
+	defer c.unlock()
 	var err error
 
 	if c.cap == 0 {
 		return err
 	}
+	if c.evict != nil {
+		c.retry(false)
+	}
+	return c.put(key, val, c.stamp(c.ttl))
+}
+
+// GetMany looks up every key in keys under a single lock acquisition, updating the recency of each entry
+// found as Get does, and returns a map of the keys that were found to their values. Keys that appear
+// more than once in keys are looked up once per appearance.
+func (c *Cache[K, V]) GetMany(keys []K) map[K]V {
+	found := make(map[K]V, len(keys))
+	c.lock()
+	defer c.unlock()
+	for _, key := range keys {
+		if val, ok := c.get(key); ok {
+			found[key] = val
+		}
+	}
+	return found
+}
+
+// PutMany adds every key-value pair yielded by pairs to the Cache under a single lock acquisition, as if
+// by calls to Put in the same order, and returns the errors Put would have returned. pairs is iterated
+// while the Cache is locked, so it must not call methods on the Cache.
+func (c *Cache[K, V]) PutMany(pairs iter.Seq2[K, V]) error {
+	c.lock()
+	defer c.unlock()
+	if c.cap == 0 {
+		return nil
+	}
+	if c.evict != nil {
+		c.retry(false)
+	}
+	var err error
+	for key, val := range pairs {
+		err = errors.Join(err, c.put(key, val, c.stamp(c.ttl)))
+	}
+	return err
+}
+
+// put adds a key-value pair written with the times in s to the Cache, which must be locked and have a
+// non-zero capacity.
+func (c *Cache[K, V]) put(key K, val V, s stamp) error {
+	var err error
+	if c.freq != nil {
+		c.freq.add(key, c.cap)
+	}
 
 	// if the key is cached, just update the val and move to front
 	i, ok := c.keys[key]
 	if ok {
+		if c.data[i].ro {
+			return ErrReadOnly
+		}
+		if c.weigh != nil {
+			w := c.weigh(key, val)
+			c.weight -= c.data[i].wt
+			if err = c.fit(w, key); err != nil {
+				c.weight += c.data[c.keys[key]].wt
+				return err
+			}
+			// making room may have moved the node
+			i = c.keys[key]
+			c.weight += w
+			c.data[i].wt = w
+		}
+		c.version++
+		c.changes.Add(1)
+		c.unindex(key, c.data[i].val)
+		c.index(key, val)
 		c.data[i].val = val
-		c.promote(i)
+		c.data[i].ver = c.version
+		c.data[i].exp = s.exp
+		c.data[i].at = s.at
+		if !c.untouched {
+			c.touch(i)
+			c.promote(i)
+		}
+		if len(c.watches) > 0 {
+			c.notify(WatchUpdated, key, val, 0)
+		}
+		if c.origins != nil {
+			c.noteOrigin(key, err)
+		}
 		return err
 	}
+	return c.add(key, val, s)
+}
+
+// add inserts a key-value pair that is not already cached and is written with the times in s, evicting an
+// entry if the Cache is full.
+func (c *Cache[K, V]) add(key K, val V, s stamp) (err error) {
+	if c.origins != nil {
+		defer func() { c.noteOrigin(key, err) }()
+	}
+	if len(c.quarantined) > 0 {
+		err = c.unquarantine(key, Evicted)
+	}
+	var w uint64
+	if c.weigh != nil {
+		w = c.weigh(key, val)
+	}
+	if c.admit != nil && !c.admitted(key, val, w) || c.freq != nil && !c.frequent(key) {
+		return errors.Join(err, ErrNotAdmitted)
+	}
+	if c.weigh != nil {
+		if ferr := c.fit(w, key); ferr != nil {
+			return errors.Join(err, ferr)
+		}
+	}
+
+	t, over := c.overQuota(key)
+	if !over && c.low < c.high && uint64(c.len) >= min(c.high, c.cap) {
+		if c.wake != nil {
+			c.signalEviction()
+		} else {
+			err = errors.Join(err, c.shrink(c.low))
+		}
+	}
 
 	// if there's space, no need to evict
-	if uint64(c.len) < c.cap {
+	if !over && uint64(c.len) < c.cap {
 		// take the highest unused
+		c.insertions.Add(1)
+		c.version++
+		c.changes.Add(1)
+		c.clock++
 		c.data[c.len] = node[K, V]{
 			next: c.head,
 			key:  key,
 			val:  val,
+			tick: c.clock,
+			ver:  c.version,
+			ins:  c.version,
+			exp:  s.exp,
+			at:   s.at,
+			born: s.at,
+			used: s.at,
+			wt:   w,
 		}
 		c.data[c.head].last = c.len
 		// no need to update the tail; the initial tail will be at index 0
 		c.head = c.len
 		c.keys[key] = c.len
 		c.len++
+		c.track(key)
+		c.index(key, val)
+		c.weight += w
+		if c.midpoint && c.score == nil {
+			c.toMidpoint(c.keys[key])
+		}
+		if len(c.watches) > 0 {
+			c.notify(WatchInserted, key, val, 0)
+		}
 		return err
 	}
 
-	victim := &c.data[c.tail]
-	if c.evict != nil {
-		err = c.evict(victim.key, victim.val)
+	var i int
+	var ok bool
+	if over {
+		i, ok = c.tenantVictim(t)
+	} else {
+		i, ok = c.victim()
 	}
+	if !ok {
+		return errors.Join(err, ErrNoVictim)
+	}
+	c.insertions.Add(1)
+	c.version++
+	c.changes.Add(1)
+	err = errors.Join(err, c.expel(i))
+	victim := &c.data[i]
 
-	// take from the tail
+	// reuse the victim's node
+	if c.midpoint {
+		c.leaveOld(i)
+	}
 	delete(c.keys, victim.key)
-	c.keys[key] = c.tail
+	c.keys[key] = i
+	c.untrack(victim.key, true)
+	c.track(key)
+	c.unindex(victim.key, victim.val)
+	c.index(key, val)
+	if c.origins != nil {
+		delete(c.origins, victim.key)
+	}
+	c.weight += w - victim.wt
 
-	victim.key = key
-	victim.val = val
+	c.clock++
+	*victim = node[K, V]{
+		next: victim.next,
+		last: victim.last,
+		key:  key,
+		val:  val,
+		tick: c.clock,
+		ver:  c.version,
+		ins:  c.version,
+		exp:  s.exp,
+		at:   s.at,
+		born: s.at,
+		used: s.at,
+		wt:   w,
+	}
 
-	c.promote(c.tail)
+	if c.midpoint && c.score == nil {
+		c.toMidpoint(i)
+	} else {
+		c.promote(i)
+	}
+	if len(c.watches) > 0 {
+		c.notify(WatchInserted, key, val, 0)
+	}
 	return err
 }
 
+// GetOrPutFunc returns the cached value associated with key and true if the key was found. Otherwise, it
+// calls fn, adds the value it returns to the Cache, and returns that value and false, along with any error
+// returned by evict as for Put. The whole operation is atomic and performs a single lookup of key; fn is
+// called while the Cache is locked, so it must not call methods on the Cache.
+func (c *Cache[K, V]) GetOrPutFunc(key K, fn func() V) (V, bool, error) {
+	c.lock()
+	defer c.unlock()
+	i, ok := c.lookup(key)
+	if ok {
+		c.touch(i)
+		c.promote(i)
+		return c.data[i].val, true, nil
+	}
+	val := fn()
+	if c.cap == 0 {
+		return val, false, nil
+	}
+	if c.evict != nil {
+		c.retry(false)
+	}
+	return val, false, c.add(key, val, c.stamp(c.ttl))
+}
+
+// Update atomically replaces the value cached for key with the result of fn, which is called with the
+// cached value and true, or with the zero value and false if the key is not cached. If fn's second result
+// is false, nothing is stored. Otherwise, the new value is written as by Put, and Update returns any error
+// Put would. fn is called while the Cache is locked, so it must not call methods on the Cache.
+func (c *Cache[K, V]) Update(key K, fn func(V, bool) (V, bool)) error {
+	c.lock()
+	defer c.unlock()
+	i, ok, err := c.find(key)
+	var old V
+	if ok {
+		old = c.data[i].val
+	}
+	val, store := fn(old, ok)
+	if !store || c.cap == 0 {
+		return err
+	}
+	if c.evict != nil {
+		c.retry(false)
+	}
+	return errors.Join(err, c.put(key, val, c.stamp(c.ttl)))
+}
+
+// GetOrPut returns the cached value associated with key and true if the key was found, updating its
+// recency as Get does. Otherwise, it adds the key-value pair to the Cache and returns val and false, along
+// with any error returned as for Put. Like sync.Map's LoadOrStore, the whole operation is atomic, so of
+// several goroutines that call it for the same key, only one stores its value.
+func (c *Cache[K, V]) GetOrPut(key K, val V) (actual V, loaded bool, err error) {
+	return c.GetOrPutFunc(key, func() V { return val })
+}
+
+// ContainsOrAdd checks whether key is cached without updating its recency and, if not, adds the key-value
+// pair to the Cache. It reports whether the key already existed and whether adding it evicted any other
+// entries, along with any error returned as for Put.
+func (c *Cache[K, V]) ContainsOrAdd(key K, val V) (existed, evicted bool, err error) {
+	_, existed, evicted, err = c.PeekOrAdd(key, val)
+	return existed, evicted, err
+}
+
+// PeekOrAdd returns the value cached for key without updating its recency, or, if the key is not cached,
+// adds the key-value pair to the Cache. It reports whether the key already existed and whether adding it
+// evicted any other entries, along with any error returned as for Put.
+func (c *Cache[K, V]) PeekOrAdd(key K, val V) (prev V, existed, evicted bool, err error) {
+	c.lock()
+	defer c.unlock()
+	i, ok, err := c.find(key)
+	if ok {
+		return c.data[i].val, true, false, nil
+	}
+	if c.cap == 0 {
+		return prev, false, false, err
+	}
+	if c.evict != nil {
+		c.retry(false)
+	}
+	// a single add may evict several entries, for instance to make room by weight
+	n := c.expelled
+	err = errors.Join(err, c.add(key, val, c.stamp(c.ttl)))
+	return prev, false, c.expelled != n, err
+}
+
+// MapValues replaces the value of every cached entry with the value fn returns for it, under a single
+// lock acquisition unless WithChunkSize is set, and without changing the recency order. Expired and
+// read-only entries are skipped. Each rewritten entry gets a new version. fn must not call methods on the
+// Cache.
+func (c *Cache[K, V]) MapValues(fn func(K, V) V) {
+	c.lock()
+	defer c.unlock()
+	c.beginBatch()
+	defer c.endBatch()
+	live := c.live()
+	start := c.version
+	var visited int
+	// walk backwards for the same reason as removeWhere; entries written since the start are either
+	// rewritten already or were written while the lock was released
+	for i := c.len - 1; i >= 0; i = min(i-1, c.len-1) {
+		if n := &c.data[i]; !n.ro && live.ok(n) && n.ver <= start {
+			c.version++
+			c.changes.Add(1)
+			c.unindex(n.key, n.val)
+			n.val = fn(n.key, n.val)
+			c.index(n.key, n.val)
+			n.ver = c.version
+			if len(c.watches) > 0 {
+				c.notify(WatchUpdated, n.key, n.val, 0)
+			}
+		}
+		visited++
+		c.pause(visited)
+	}
+}
+
+// GetOldest is equivalent to Oldest.
+func (c *Cache[K, V]) GetOldest() (K, V, bool) {
+	return c.Oldest()
+}
+
+// Oldest returns the least-recently used unexpired entry without removing it or updating its recency,
+// and true, or false if the Cache holds no such entry.
+func (c *Cache[K, V]) Oldest() (K, V, bool) {
+	for key, val := range c.Backward() {
+		return key, val, true
+	}
+	return *new(K), *new(V), false
+}
+
+// PopOldest removes the least-recently used unexpired entry from the Cache and returns it and true, or
+// false if the Cache holds no such entry, so that a Cache can be drained from its cold end. The evict func
+// is not called for the entry, since it is handed to the caller instead. Expired entries found along the
+// way are removed as by Get.
+func (c *Cache[K, V]) PopOldest() (K, V, bool) {
+	c.lock()
+	defer c.unlock()
+	for c.len > 0 {
+		i := c.tail
+		n := c.data[i]
+		c.remove(i, false)
+		if n.exp != 0 && c.now() >= n.exp {
+			c.discard(n.key, n.val, Expired)
+			continue
+		}
+		// the entry is the caller's now, so releasing a Handle to it must not dispose of it
+		delete(c.pins, n.key)
+		if len(c.watches) > 0 {
+			c.notify(WatchRemoved, n.key, n.val, Deleted)
+		}
+		return n.key, n.val, true
+	}
+	return *new(K), *new(V), false
+}
+
+// Newest returns the most-recently used unexpired entry without updating its recency, and true, or false
+// if the Cache holds no such entry.
+func (c *Cache[K, V]) Newest() (K, V, bool) {
+	for key, val := range c.Ordered() {
+		return key, val, true
+	}
+	return *new(K), *new(V), false
+}
+
+// Remove deletes the entry for key from the Cache, calling the evict func (if it exists) with the Deleted
+// reason. It returns the removed value and true, or false if the key was not cached, along with any error
+// returned by evict.
+func (c *Cache[K, V]) Remove(key K) (V, bool, error) {
+	c.lock()
+	defer c.unlock()
+	return c.delete(key)
+}
+
+// delete implements Remove for a caller holding the write lock.
+func (c *Cache[K, V]) delete(key K) (V, bool, error) {
+	var err error
+	if len(c.quarantined) > 0 {
+		err = c.unquarantine(key, Deleted)
+	}
+	i, ok, ferr := c.find(key)
+	err = errors.Join(err, ferr)
+	if !ok {
+		return *new(V), false, err
+	}
+	n := c.data[i]
+	c.remove(i, false)
+	return n.val, true, errors.Join(err, c.discard(n.key, n.val, Deleted))
+}
+
 // Clear evicts all entries from the Cache (calling the evict func if it exists) and resets the Cache.
 // A cleared Cache is safe for re-use.
 func (c *Cache[K, V]) Clear() error {
-	c.m.Lock()
-	defer c.m.Unlock()
+	c.lock()
+	defer c.unlock()
 	var err error
 
-	if c.evict != nil {
+	c.beginBatch()
+	if c.chunk > 0 {
+		// entries may be added while the lock is released, so remove the others one at a time
+		start := c.version
+		_, err = c.removeWhere(func(n *node[K, V]) bool { return n.ins <= start }, Cleared, true)
+	} else if c.discards() || len(c.pins) > 0 || len(c.watches) > 0 {
 		var n node[K, V]
 		for _, n = range c.data[:c.len] {
-			err = errors.Join(err, c.evict(n.key, n.val))
+			err = errors.Join(err, c.discard(n.key, n.val, Cleared))
 		}
 	}
+	for len(c.quarantined) > 0 {
+		err = errors.Join(err, c.finalize(0, Cleared))
+	}
+	err = errors.Join(err, c.endBatch())
+	if c.chunk > 0 {
+		return err
+	}
+	if c.len > 0 {
+		c.changes.Add(1)
+	}
 	clear(c.data[:c.len])
 	clear(c.keys)
-	c.len = 0
+	for _, s := range c.tenants {
+		s.Len = 0
+	}
+	c.head, c.tail, c.len = 0, 0, 0
+	c.oldLen = 0
+	c.weight = 0
+	for _, x := range c.indexes {
+		x.reset()
+	}
+	clear(c.origins)
 	return err
 }
 
-// All returns an iter.Seq2 that iterates over all Cache entries.
+// All returns an iter.Seq2 that iterates over all Cache entries in no particular order; see Ordered.
+// Iteration holds a read lock on the Cache, so any number of iterations may proceed concurrently, but
+// writers wait until they finish.
 func (c *Cache[K, V]) All() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		c.m.Lock()
-		defer c.m.Unlock()
-		var n node[K, V]
-		for _, n = range c.data[:c.len] {
-			if !yield(n.key, n.val) {
+		c.rlock()
+		defer c.runlock()
+		live := c.live()
+		for i := range c.data[:c.len] {
+			n := &c.data[i]
+			if live.ok(n) && !yield(n.key, n.val) {
 				return
 			}
 		}
 	}
 }
 
-// Keys returns an iter.Seq that iterates over all cached keys.
+// Keys returns an iter.Seq that iterates over all cached keys. Like All, it holds a read lock while iterating.
 func (c *Cache[K, V]) Keys() iter.Seq[K] {
 	return func(yield func(K) bool) {
-		c.m.Lock()
-		defer c.m.Unlock()
-		var n node[K, V]
-		for _, n = range c.data[:c.len] {
-			if !yield(n.key) {
+		c.rlock()
+		defer c.runlock()
+		live := c.live()
+		for i := range c.data[:c.len] {
+			n := &c.data[i]
+			if live.ok(n) && !yield(n.key) {
 				return
 			}
 		}
 	}
 }
 
-// Values returns an iter.Seq that iterates over all cached values.
+// Values returns an iter.Seq that iterates over all cached values. Like All, it holds a read lock while
+// iterating.
 func (c *Cache[K, V]) Values() iter.Seq[V] {
 	return func(yield func(V) bool) {
-		c.m.Lock()
-		defer c.m.Unlock()
-		var n node[K, V]
-		for _, n = range c.data[:c.len] {
-			if !yield(n.val) {
+		c.rlock()
+		defer c.runlock()
+		live := c.live()
+		for i := range c.data[:c.len] {
+			n := &c.data[i]
+			if live.ok(n) && !yield(n.val) {
+				return
+			}
+		}
+	}
+}
+
+// Ordered returns an iter.Seq2 that iterates over all Cache entries from the most to the least recently
+// used. Like All, it holds a read lock while iterating. It is slower than All, since it follows the
+// recency list rather than walking the Cache's storage in sequence.
+func (c *Cache[K, V]) Ordered() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		c.rlock()
+		defer c.runlock()
+		live := c.live()
+		for i, n := c.head, 0; n < c.len; i, n = c.data[i].next, n+1 {
+			ptr := &c.data[i]
+			if live.ok(ptr) && !yield(ptr.key, ptr.val) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iter.Seq2 that iterates over all Cache entries from the least to the most recently
+// used, so that the entries that would be evicted first come first. Like All, it holds a read lock while
+// iterating.
+func (c *Cache[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		c.rlock()
+		defer c.runlock()
+		live := c.live()
+		for i, n := c.tail, 0; n < c.len; i, n = c.data[i].last, n+1 {
+			ptr := &c.data[i]
+			if live.ok(ptr) && !yield(ptr.key, ptr.val) {
+				return
+			}
+		}
+	}
+}
+
+// Inserted returns an iter.Seq2 that iterates over all Cache entries in the order in which they were
+// inserted, starting with the earliest, regardless of their recency. Writes to a cached key do not change
+// its position. Like All, it holds a read lock while iterating; before yielding the first entry, it sorts
+// the entries, which takes time and memory proportional to their number.
+func (c *Cache[K, V]) Inserted() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		c.rlock()
+		defer c.runlock()
+		live := c.live()
+		order := make([]int, 0, c.len)
+		for i := range c.data[:c.len] {
+			if live.ok(&c.data[i]) {
+				order = append(order, i)
+			}
+		}
+		slices.SortFunc(order, func(i, j int) int {
+			return cmp.Compare(c.data[i].ins, c.data[j].ins)
+		})
+		for _, i := range order {
+			if !yield(c.data[i].key, c.data[i].val) {
+				return
+			}
+		}
+	}
+}
+
+// OrderedKeys returns an iter.Seq that iterates over all cached keys from the most to the least recently
+// used. Like All, it holds a read lock while iterating.
+func (c *Cache[K, V]) OrderedKeys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range c.Ordered() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// KeysWhere returns an iter.Seq that iterates over the cached keys for which pred returns true. Like All,
+// it holds a read lock while iterating, and pred is evaluated under that lock, so it must not call methods
+// on the Cache. Values are never copied.
+func (c *Cache[K, V]) KeysWhere(pred func(K) bool) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		c.rlock()
+		defer c.runlock()
+		live := c.live()
+		for i := range c.data[:c.len] {
+			n := &c.data[i]
+			if live.ok(n) && pred(n.key) && !yield(n.key) {
 				return
 			}
 		}
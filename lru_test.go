@@ -0,0 +1,12 @@
+package lru
+
+import "testing"
+
+func TestPeekOrAddEvicted(t *testing.T) {
+	c := New[int, int](2, nil)
+	for key, want := range []bool{false, false, true} {
+		if _, _, evicted, _ := c.PeekOrAdd(key, key); evicted != want {
+			t.Errorf("PeekOrAdd(%d) reports evicted = %v, want %v", key, evicted, want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package lru
+
+import (
+	"errors"
+	"iter"
+	"path"
+)
+
+// Match returns an iter.Seq2 over the entries of c whose keys match pattern, which uses the syntax of
+// path.Match. Like All, the iterator holds a read lock on c while iterating. If pattern is malformed,
+// Match returns path.ErrBadPattern.
+func Match[V any](c *Cache[string, V], pattern string) (iter.Seq2[string, V], error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	return func(yield func(string, V) bool) {
+		c.rlock()
+		defer c.runlock()
+		live := c.live()
+		for i := range c.data[:c.len] {
+			n := &c.data[i]
+			if ok, _ := path.Match(pattern, n.key); ok && live.ok(n) && !yield(n.key, n.val) {
+				return
+			}
+		}
+	}, nil
+}
+
+// DeleteMatch removes every entry of c whose key matches pattern, which uses the syntax of path.Match,
+// calling c's evict func (if it exists) for each entry removed. It returns the number of entries removed
+// and any errors returned by evict. If pattern is malformed, DeleteMatch removes nothing and returns
+// path.ErrBadPattern.
+func DeleteMatch[V any](c *Cache[string, V], pattern string) (int, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return 0, err
+	}
+	c.lock()
+	defer c.unlock()
+	return c.deleteWhere(func(key string) bool {
+		ok, _ := path.Match(pattern, key)
+		return ok
+	}, true)
+}
+
+// deleteWhere removes every entry, including quarantined ones, whose key satisfies match, discarding them
+// with the Deleted reason. It returns the number of cached entries removed. If chunked is true, the lock
+// is released periodically as set by WithChunkSize.
+func (c *Cache[K, V]) deleteWhere(match func(K) bool, chunked bool) (int, error) {
+	c.beginBatch()
+	n, err := c.removeWhere(func(n *node[K, V]) bool { return match(n.key) }, Deleted, chunked)
+	for i := len(c.quarantined) - 1; i >= 0; i-- {
+		if match(c.quarantined[i].key) {
+			err = errors.Join(err, c.finalize(i, Deleted))
+		}
+	}
+	return n, errors.Join(err, c.endBatch())
+}
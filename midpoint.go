@@ -0,0 +1,126 @@
+package lru
+
+// WithMidpointInsertion makes new entries enter the recency list part of the way down rather than at the
+// most-recently used end, so that a scan of keys that are each requested only once evicts other scanned
+// keys instead of the working set. The part of the list behind the insertion point, which newly inserted
+// entries join, is kept at a fraction old of all entries, where old is between 0 and 1; an entry leaves
+// it for the front of the list when it is next accessed. With old at 0, entries are inserted at the
+// least-recently used end. The option is ignored by WithScorer and WithSampledLRU, which do not evict
+// in list order.
+func WithMidpointInsertion[K comparable, V any](old float64) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.midpoint = true
+		c.slru = false
+		c.oldFrac = min(max(old, 0), 1)
+	}
+}
+
+// WithSegmentedLRU splits the recency list into a protected segment at the front and a probationary
+// segment behind it, as in a segmented LRU or 2Q cache, so that a scan of keys that are each requested
+// only once cannot evict the working set. New entries join the front of the probationary segment and
+// only enter the protected segment when they are accessed again; entries are evicted from the
+// probationary segment first. The protected segment holds at most a fraction protected of the Cache's
+// capacity, where protected is between 0 and 1; when it is full, its least-recently used entry is moved
+// back to the front of the probationary segment. Unlike with WithMidpointInsertion, an entry that has not
+// been accessed since its insertion never enters the protected segment, however empty it is. The option
+// replaces WithMidpointInsertion, and is ignored by WithScorer and WithSampledLRU.
+func WithSegmentedLRU[K comparable, V any](protected float64) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.midpoint = true
+		c.slru = true
+		c.oldFrac = 1 - min(max(protected, 0), 1)
+	}
+}
+
+// leaveOld removes the node at index i, which must still be linked, from the old part of the list.
+func (c *Cache[K, V]) leaveOld(i int) {
+	ptr := &c.data[i]
+	if !ptr.old {
+		return
+	}
+	ptr.old = false
+	c.oldLen--
+	if c.oldLen > 0 && c.mid == i {
+		// the old part of the list is contiguous, so the next node must be old too
+		c.mid = ptr.next
+	}
+}
+
+// toMidpoint moves the node at index i, which must not be old, to the front of the old part of the list.
+func (c *Cache[K, V]) toMidpoint(i int) {
+	if c.len > 1 {
+		c.unlink(i)
+		if c.oldLen == 0 {
+			c.linkTail(i)
+		} else {
+			c.linkBefore(i, c.mid)
+		}
+	}
+	c.data[i].old = true
+	c.mid = i
+	c.oldLen++
+	c.rebalance()
+}
+
+// rebalance moves the boundary between the new and old parts of the list until the old part is the
+// configured fraction of the list. With WithSegmentedLRU, it only moves nodes from the new part to the old
+// part, and only while the new part is over its limit.
+func (c *Cache[K, V]) rebalance() {
+	target := int(float64(c.len) * c.oldFrac)
+	if c.slru {
+		target = max(c.len-int(float64(c.cap)*(1-c.oldFrac)), 0)
+		if c.oldLen >= target {
+			return
+		}
+	}
+	for c.oldLen < target {
+		j := c.tail
+		if c.oldLen > 0 {
+			j = c.data[c.mid].last
+		}
+		c.data[j].old = true
+		c.mid = j
+		c.oldLen++
+	}
+	for c.oldLen > target {
+		c.data[c.mid].old = false
+		c.oldLen--
+		c.mid = c.data[c.mid].next
+	}
+}
+
+// unlink detaches the node at index i from its neighbors. The list must have more than one node.
+func (c *Cache[K, V]) unlink(i int) {
+	ptr := &c.data[i]
+	if i == c.head {
+		c.head = ptr.next
+	} else {
+		c.data[ptr.last].next = ptr.next
+	}
+	if i == c.tail {
+		c.tail = ptr.last
+	} else {
+		c.data[ptr.next].last = ptr.last
+	}
+}
+
+// linkBefore links the unlinked node at index i in front of the node at index j.
+func (c *Cache[K, V]) linkBefore(i, j int) {
+	ptr := &c.data[i]
+	ptr.next = j
+	if j == c.head {
+		c.head = i
+	} else {
+		ptr.last = c.data[j].last
+		c.data[ptr.last].next = i
+	}
+	c.data[j].last = i
+}
+
+// linkTail links the unlinked node at index i at the tail of the list.
+func (c *Cache[K, V]) linkTail(i int) {
+	ptr := &c.data[i]
+	ptr.last = c.tail
+	c.data[c.tail].next = i
+	c.tail = i
+}
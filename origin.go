@@ -0,0 +1,106 @@
+package lru
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// pkgPrefix is the prefix of the names of the functions in this package, used to find the caller of a
+// write outside of it.
+var pkgPrefix = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+	slash := strings.LastIndexByte(name, '/')
+	dot := strings.IndexByte(name[slash+1:], '.')
+	return name[:slash+1+dot+1]
+}()
+
+// EntryMetadata describes how a cached entry came to be, for debugging. See GetEntry.
+type EntryMetadata struct {
+	Origin   string    // where the entry was last written; see WithOrigins
+	Written  time.Time // when the entry was last written, if recorded; see WithWriteTimes
+	Expires  time.Time // when the entry expires, or the zero Time if it does not
+	Version  uint64    // see GetVersion
+	Hits     uint64    // accesses since the entry was inserted
+	ReadOnly bool      // see SetReadOnly
+}
+
+// WithOrigins makes the Cache record where each entry was last written, so that the code path that
+// inserted a bad value can be found. Unless the write was made by PutWithOrigin, the origin is the file
+// and line of the first caller outside of this package. Recording it costs a stack walk per write.
+func WithOrigins[K comparable, V any]() Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.origins = make(map[K]string)
+	}
+}
+
+// PutWithOrigin behaves like Put, but records origin as the origin of the entry if WithOrigins is used.
+func (c *Cache[K, V]) PutWithOrigin(key K, val V, origin string) error {
+	c.lock()
+	defer c.unlock()
+	if c.cap == 0 {
+		return nil
+	}
+	if c.evict != nil {
+		c.retry(false)
+	}
+	c.origin = origin
+	defer func() { c.origin = "" }()
+	return c.put(key, val, c.stamp(c.ttl))
+}
+
+// GetEntry returns the cached value associated with key and its metadata without updating its recency,
+// and true, or false if the key was not found. The metadata include an origin only if WithOrigins is used.
+func (c *Cache[K, V]) GetEntry(key K) (V, EntryMetadata, bool) {
+	c.rlock()
+	defer c.runlock()
+	i, ok := c.keys[key]
+	if !ok || c.expired(i) {
+		return *new(V), EntryMetadata{}, false
+	}
+	n := &c.data[i]
+	md := EntryMetadata{
+		Origin:   c.origins[key],
+		Version:  n.ver,
+		Hits:     atomic.LoadUint64(&n.hits), // see shared
+		ReadOnly: n.ro,
+	}
+	if n.at != 0 {
+		md.Written = c.epoch.Add(time.Duration(n.at - 1))
+	}
+	if n.exp != 0 {
+		md.Expires = c.epoch.Add(time.Duration(n.exp - 1))
+	}
+	return n.val, md, true
+}
+
+// noteOrigin records the origin of a write to key that returned err.
+func (c *Cache[K, V]) noteOrigin(key K, err error) {
+	if _, ok := c.keys[key]; !ok || errors.Is(err, ErrReadOnly) {
+		return
+	}
+	origin := c.origin
+	if origin == "" {
+		origin = caller()
+	}
+	c.origins[key] = origin
+}
+
+// caller returns the file and line of the innermost caller outside of this package.
+func caller() string {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, pkgPrefix) {
+			return f.File + ":" + strconv.Itoa(f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
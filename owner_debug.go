@@ -0,0 +1,50 @@
+//go:build lrudebug
+
+package lru
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// owner records the ids of the goroutines holding a Cache's mutex so that re-entrant calls can be reported.
+type owner struct {
+	m    sync.Mutex
+	gids map[uint64]struct{}
+}
+
+func (o *owner) check() {
+	o.m.Lock()
+	_, held := o.gids[goid()]
+	o.m.Unlock()
+	if held {
+		panic("lru: Cache re-entered by a goroutine holding its lock; an evict func or iterator body must not call methods on the same Cache")
+	}
+}
+
+func (o *owner) set() {
+	o.m.Lock()
+	if o.gids == nil {
+		o.gids = make(map[uint64]struct{})
+	}
+	o.gids[goid()] = struct{}{}
+	o.m.Unlock()
+}
+
+func (o *owner) unset() {
+	o.m.Lock()
+	delete(o.gids, goid())
+	o.m.Unlock()
+}
+
+// goid returns the id of the calling goroutine, parsed from its stack trace header.
+func goid() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	b, _, _ = bytes.Cut(b, []byte(" "))
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
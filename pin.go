@@ -0,0 +1,144 @@
+package lru
+
+// pin counts the Handles to a cached entry. If the entry leaves the Cache while it is pinned, its disposal
+// is deferred until the last Handle is released.
+type pin[V any] struct {
+	refs   int
+	held   bool // whether the entry is pinned by Pin
+	gone   bool
+	val    V
+	reason Reason
+}
+
+// A Handle pins a cached entry, which may not be evicted to make room for new entries until the Handle is
+// released; pinned entries are passed over when choosing a victim, as if vetoed by WithCanEvict.
+// If the entry is removed while it is pinned, for instance by Remove, Clear, or expiration, it is no
+// longer cached, but the evict func is not called for it until every Handle to it is released. This makes
+// it safe to cache values, such as memory-mapped regions or pooled connections, that the evict func
+// closes. A Handle must be released exactly once.
+type Handle[K comparable, V any] struct {
+	c        *Cache[K, V]
+	p        *pin[V]
+	key      K
+	val      V
+	released bool
+}
+
+// Acquire is like Get, but also returns a Handle that pins the entry for key until it is released. It
+// returns nil and false if the key was not found.
+func (c *Cache[K, V]) Acquire(key K) (*Handle[K, V], bool) {
+	c.lock()
+	defer c.unlock()
+	val, ok := c.get(key)
+	if !ok {
+		return nil, false
+	}
+	p := c.pins[key]
+	if p == nil {
+		if c.pins == nil {
+			c.pins = make(map[K]*pin[V])
+		}
+		p = new(pin[V])
+		c.pins[key] = p
+	}
+	p.refs++
+	return &Handle[K, V]{c: c, p: p, key: key, val: val}, true
+}
+
+// Pin pins the entry for key, without updating its recency, until Unpin is called for the key or the
+// entry is removed from the Cache. Like entries pinned by a Handle, it is passed over when choosing a
+// victim, so the next-oldest unpinned entry is evicted instead; if every entry is pinned, Put returns
+// ErrNoVictim. Unlike a Handle, Pin does not defer the disposal of the entry once it is removed, and
+// pinning an entry more than once has no further effect. Pin reports whether the key was found.
+func (c *Cache[K, V]) Pin(key K) bool {
+	c.lock()
+	defer c.unlock()
+	i, ok := c.keys[key]
+	if !ok || c.expired(i) {
+		return false
+	}
+	p := c.pins[key]
+	if p == nil {
+		if c.pins == nil {
+			c.pins = make(map[K]*pin[V])
+		}
+		p = new(pin[V])
+		c.pins[key] = p
+	}
+	p.held = true
+	return true
+}
+
+// Unpin undoes Pin for key. Handles to the entry are unaffected. Unpin reports whether the entry had been
+// pinned by Pin.
+func (c *Cache[K, V]) Unpin(key K) bool {
+	c.lock()
+	defer c.unlock()
+	p, ok := c.pins[key]
+	if !ok || !p.held {
+		return false
+	}
+	p.held = false
+	if p.refs == 0 {
+		delete(c.pins, key)
+	}
+	return true
+}
+
+// Key returns the key of the pinned entry.
+func (h *Handle[K, V]) Key() K {
+	return h.key
+}
+
+// Value returns the value of the pinned entry at the time it was acquired.
+func (h *Handle[K, V]) Value() V {
+	return h.val
+}
+
+// Release unpins the entry. If it was the last Handle to an entry that has since been removed from the
+// Cache, the evict func is called for the entry, and Release returns any error it returns.
+func (h *Handle[K, V]) Release() error {
+	c := h.c
+	c.lock()
+	defer c.unlock()
+	if h.released {
+		return nil
+	}
+	h.released = true
+	p := h.p
+	if p.refs--; p.refs > 0 {
+		return nil
+	}
+	if !p.gone {
+		if c.pins[h.key] == p && !p.held {
+			delete(c.pins, h.key)
+		}
+		return nil
+	}
+	return c.dispose(h.key, p.val, p.reason)
+}
+
+// pinned reports whether the entry for key is pinned by a Handle.
+func (c *Cache[K, V]) pinned(key K) bool {
+	if len(c.pins) == 0 {
+		return false
+	}
+	_, ok := c.pins[key]
+	return ok
+}
+
+// deferDiscard reports whether the disposal of an entry that has left the Cache must wait for its Handles to be
+// released, in which case it records the reason for when they are.
+func (c *Cache[K, V]) deferDiscard(key K, val V, reason Reason) bool {
+	p, ok := c.pins[key]
+	if !ok {
+		return false
+	}
+	delete(c.pins, key)
+	if p.refs == 0 {
+		// only held by Pin, which does not outlive the entry
+		return false
+	}
+	p.gone, p.val, p.reason = true, val, reason
+	return true
+}
@@ -0,0 +1,112 @@
+package lru
+
+import (
+	"errors"
+	"time"
+)
+
+type qEntry[K comparable, V any] struct {
+	key   K
+	val   V
+	exp   int64 // the entry's expiration time; see Cache.now
+	at    int64 // the time of the entry's last write
+	until time.Time
+}
+
+// WithQuarantine gives entries evicted to make room for new ones a second chance. Instead of being
+// dropped, up to size of the most recently evicted entries are held in quarantine for the duration of
+// window; a Get for a quarantined key rescues the entry back into the Cache and counts as a hit. The evict
+// func is only called once an entry leaves quarantine without being rescued, or when it is displaced by a
+// Put, Clear, or DeleteMatch. Errors returned by evict for entries leaving quarantine during a Get are
+// discarded unless WithEvictRetry is also used.
+func WithQuarantine[K comparable, V any](size int, window time.Duration) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.qsize = size
+		c.qwindow = window
+	}
+}
+
+// Rescues returns the number of entries that have been rescued from quarantine by a Get.
+func (c *Cache[K, V]) Rescues() uint64 {
+	c.rlock()
+	defer c.runlock()
+	return c.rescues
+}
+
+// quarantine moves an evicted entry into quarantine, finalizing any entries that have expired or that
+// must make room for it.
+func (c *Cache[K, V]) quarantine(key K, val V, exp, at int64) error {
+	now := time.Now()
+	err := c.expireQuarantine(now)
+	if len(c.quarantined) >= c.qsize {
+		err = errors.Join(err, c.finalize(0, Evicted))
+	}
+	c.quarantined = append(c.quarantined, qEntry[K, V]{key: key, val: val, exp: exp, at: at, until: now.Add(c.qwindow)})
+	return err
+}
+
+// expireQuarantine finalizes all quarantined entries whose window has passed.
+func (c *Cache[K, V]) expireQuarantine(now time.Time) error {
+	var err error
+	for len(c.quarantined) > 0 && !now.Before(c.quarantined[0].until) {
+		err = errors.Join(err, c.finalize(0, Evicted))
+	}
+	return err
+}
+
+// finalize removes the quarantined entry at index i and discards it for the given reason.
+func (c *Cache[K, V]) finalize(i int, reason Reason) error {
+	q := c.quarantined[i]
+	copy(c.quarantined[i:], c.quarantined[i+1:])
+	c.quarantined[len(c.quarantined)-1] = qEntry[K, V]{}
+	c.quarantined = c.quarantined[:len(c.quarantined)-1]
+	// the entry's removal was reported when it was quarantined
+	return c.dispose(q.key, q.val, reason)
+}
+
+// unquarantine finalizes the quarantined entry for key, if there is one, so that a stale value cannot be
+// rescued after key is overwritten or deleted.
+func (c *Cache[K, V]) unquarantine(key K, reason Reason) error {
+	for i := range c.quarantined {
+		if c.quarantined[i].key == key {
+			return c.finalize(i, reason)
+		}
+	}
+	return nil
+}
+
+// rescue moves the quarantined entry for key back into the Cache and returns its index. It returns false
+// if key is not quarantined or the entry cannot be re-admitted.
+func (c *Cache[K, V]) rescue(key K) (int, bool) {
+	c.expireQuarantine(time.Now())
+	for i, q := range c.quarantined {
+		if q.key != key {
+			continue
+		}
+		if q.exp != 0 && c.now() >= q.exp {
+			c.finalize(i, Expired)
+			return 0, false
+		}
+		copy(c.quarantined[i:], c.quarantined[i+1:])
+		c.quarantined[len(c.quarantined)-1] = qEntry[K, V]{}
+		c.quarantined = c.quarantined[:len(c.quarantined)-1]
+		s := stamp{exp: q.exp}
+		if c.stamped {
+			s.at = c.now()
+		}
+		c.put(q.key, q.val, s)
+		if _, ok := c.keys[key]; !ok {
+			// the entry was not re-admitted, for instance for lack of a victim, so put it back where it was
+			c.quarantined = append(c.quarantined, qEntry[K, V]{})
+			copy(c.quarantined[i+1:], c.quarantined[i:])
+			c.quarantined[i] = q
+			return 0, false
+		}
+		c.rescues++
+		i = c.keys[key]
+		// a rescue is not a write
+		c.data[i].at = q.at
+		return i, true
+	}
+	return 0, false
+}
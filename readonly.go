@@ -0,0 +1,19 @@
+package lru
+
+import "errors"
+
+// ErrReadOnly is returned by Put and similar methods when the entry for the key is read-only.
+var ErrReadOnly = errors.New("lru: entry is read-only")
+
+// SetReadOnly marks the entry for key as read-only, if ro is true, or as writable again. While an entry
+// is read-only, writes to its key fail with ErrReadOnly, but it can still be evicted, expire, or be
+// deleted, after which the flag no longer applies. SetReadOnly reports whether key was found.
+func (c *Cache[K, V]) SetReadOnly(key K, ro bool) bool {
+	c.lock()
+	defer c.unlock()
+	i, ok, _ := c.find(key)
+	if ok {
+		c.data[i].ro = ro
+	}
+	return ok
+}
@@ -0,0 +1,51 @@
+package lru
+
+// A Locked gives an evict func set with WithEvictLocked restricted access to the Cache it is disposing of
+// an entry for, without deadlocking on the lock the Cache holds during the call. It may only be used
+// until the evict func returns.
+type Locked[K comparable, V any] struct {
+	c    *Cache[K, V]
+	held bool // whether the Cache's lock is held for the caller
+}
+
+// WithEvictLocked sets an evict func that is also passed a Locked, through which it may look up entries
+// of the Cache and schedule work on it, and told why each entry left the Cache. It replaces the evict func
+// passed to New. A call that needs to write to the Cache, such as moving the entry to a different key,
+// must be made through Locked.Defer.
+func WithEvictLocked[K comparable, V any](evict func(l *Locked[K, V], key K, val V, reason Reason) error) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.evictLocked = evict
+		c.batch, c.batching = nil, 0
+		c.evict = func(key K, val V, reason Reason) error {
+			return evict(&Locked[K, V]{c: c, held: true}, key, val, reason)
+		}
+	}
+}
+
+// Peek behaves like Cache.Peek. Entries that are leaving the Cache in the same operation as the one being
+// disposed of, for instance by Clear, may still be found.
+func (l *Locked[K, V]) Peek(key K) (V, bool) {
+	if !l.held {
+		return l.c.Peek(key)
+	}
+	return l.c.peek(key)
+}
+
+// Contains behaves like Cache.Contains, with the same caveat as Peek.
+func (l *Locked[K, V]) Contains(key K) bool {
+	if !l.held {
+		return l.c.Contains(key)
+	}
+	return l.c.contains(key)
+}
+
+// Defer arranges for fn to be called with the Cache once its lock has been released, before the method
+// that disposed of the entry returns, so that fn may call any of the Cache's methods. If the evict func
+// runs without the lock, as with WithAsyncEvict, fn is called right away.
+func (l *Locked[K, V]) Defer(fn func(*Cache[K, V])) {
+	if !l.held {
+		fn(l.c)
+		return
+	}
+	l.c.after = append(l.c.after, fn)
+}
@@ -0,0 +1,30 @@
+package lru
+
+import "errors"
+
+// Resize changes the capacity of the Cache to cap entries, reallocating its storage so that a Cache that
+// shrinks also releases memory. If the Cache holds more than cap entries, the least-recently used ones are
+// evicted first, and Resize returns any errors returned by the evict func. If too few entries may be
+// evicted (see WithCanEvict), the capacity is only reduced to the number of entries that remain, and
+// Resize also returns ErrNoVictim.
+func (c *Cache[K, V]) Resize(cap uint64) error {
+	c.lock()
+	defer c.unlock()
+	err := c.shrink(cap)
+	if uint64(c.len) > cap {
+		err = errors.Join(err, ErrNoVictim)
+		cap = uint64(c.len)
+	}
+
+	data := make([]node[K, V], cap)
+	copy(data, c.data[:c.len])
+	keys := make(map[K]int, cap)
+	for key, i := range c.keys {
+		keys[key] = i
+	}
+	c.data, c.keys, c.cap = data, keys, cap
+	if c.freq != nil {
+		c.freq.resize(cap)
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package lru
+
+import (
+	"errors"
+	"time"
+)
+
+// maxBackoffShift caps the exponential growth of the retry delay at backoff<<maxBackoffShift.
+const maxBackoffShift = 10
+
+type retryEntry[K comparable, V any] struct {
+	key    K
+	val    V
+	reason Reason
+	tries  int
+	at     time.Time // time of the next attempt
+}
+
+// WithEvictRetry parks entries whose evict func call failed on a queue of up to size entries instead of
+// dropping them. Parked entries are no longer cached, but the evict func is called for them again with
+// exponential backoff, starting at backoff, during subsequent calls to Put or RetryEvictions, until it
+// succeeds. An error is only returned to the caller when an entry cannot be parked because the queue is
+// full.
+func WithEvictRetry[K comparable, V any](size int, backoff time.Duration) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.retrySize = size
+		c.backoff = backoff
+	}
+}
+
+// retry calls the evict func for parked entries whose next attempt is due, or for all parked entries if
+// force is true. It returns the errors of the attempts that failed.
+func (c *Cache[K, V]) retry(force bool) error {
+	if len(c.retries) == 0 {
+		return nil
+	}
+	var errs error
+	now := time.Now()
+	kept := c.retries[:0]
+	for _, r := range c.retries {
+		if !force && now.Before(r.at) {
+			kept = append(kept, r)
+			continue
+		}
+		err := c.evict(r.key, r.val, r.reason)
+		if err == nil {
+			if c.recycle != nil {
+				c.recycle(r.val)
+			}
+			continue
+		}
+		errs = errors.Join(errs, err)
+		r.tries++
+		r.at = now.Add(c.backoff << min(r.tries, maxBackoffShift))
+		kept = append(kept, r)
+	}
+	clear(c.retries[len(kept):])
+	c.retries = kept
+	return errs
+}
+
+// RetryEvictions immediately calls the evict func for every entry parked on the retry queue (see
+// WithEvictRetry), regardless of backoff. Entries for which it fails again remain parked, and their
+// errors are returned.
+func (c *Cache[K, V]) RetryEvictions() error {
+	c.lock()
+	defer c.unlock()
+	return c.retry(true)
+}
+
+// PendingEvictions returns the number of entries parked on the retry queue.
+func (c *Cache[K, V]) PendingEvictions() int {
+	c.rlock()
+	defer c.runlock()
+	return len(c.retries)
+}
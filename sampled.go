@@ -0,0 +1,57 @@
+package lru
+
+import "sync/atomic"
+
+// WithSampledLRU trades exact LRU ordering for cheaper reads. Get no longer reorders the recency list;
+// it only records the time of the access in the entry and counts the hit, which it can do while holding
+// a read lock, so concurrent Gets do not serialize. When the Cache is full, samples entries are chosen
+// at random and the one accessed longest ago is evicted, as in Redis's approximated LRU. If samples is
+// less than 1, 5 entries are sampled. Attached shadows, quarantined entries, tenant statistics, watches
+// (see Watch), and WithTinyLFU require Get to take the write lock, so they forfeit the benefit.
+// WithSampledLRU replaces any scoring func set with WithScorer.
+func WithSampledLRU[K comparable, V any](samples int) Option[K, V] {
+	scorer := WithScorer(samples, func(_ K, _ V, e EntryInfo) float64 {
+		return -float64(e.Age)
+	})
+	return func(c *Cache[K, V]) {
+		scorer(c)
+		c.sampled = true
+	}
+}
+
+// getShared implements Get for a Cache using sampled LRU. The returned done is false if the lookup needs
+// the write lock after all.
+func (c *Cache[K, V]) getShared(key K) (val V, ok, done bool) {
+	c.rlock()
+	defer c.runlock()
+	return c.shared(key)
+}
+
+// shared implements getShared for a caller holding the read lock.
+func (c *Cache[K, V]) shared(key K) (val V, ok, done bool) {
+	if len(c.shadows) > 0 || len(c.quarantined) > 0 || c.tenant != nil || len(c.watches) > 0 ||
+		c.freq != nil {
+		return val, false, false
+	}
+	i, ok := c.keys[key]
+	if ok && c.data[i].exp != 0 && c.expired(i) {
+		// removing the entry requires the write lock
+		return val, false, false
+	}
+	if ok && c.validate != nil && !c.validate(key, c.data[i].val) {
+		return val, false, false
+	}
+	if ok {
+		// the clock only advances under the write lock, so it need not be loaded atomically; the other
+		// fields are updated atomically because concurrent Gets may share the read lock
+		n := &c.data[i]
+		atomic.StoreUint64(&n.tick, c.clock)
+		atomic.AddUint64(&n.hits, 1)
+		if c.accessed {
+			atomic.StoreInt64(&n.used, c.now())
+		}
+		val = n.val
+	}
+	c.count(ok)
+	return val, ok, true
+}
@@ -0,0 +1,47 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSampledAccess checks that Gets served under the read lock still count as accesses of the entry.
+func TestSampledAccess(t *testing.T) {
+	c := New[int, int](4, nil, WithSampledLRU[int, int](0), WithAccessTimes[int, int]())
+	c.Put(1, 1)
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := c.Get(1); !ok {
+		t.Fatal("Get(1) missed")
+	}
+	if _, md, _ := c.GetEntry(1); md.Hits != 1 {
+		t.Errorf("GetEntry(1) reports %d hits, want 1", md.Hits)
+	}
+	h := c.Ages(10 * time.Millisecond)
+	if h.Accessed[0] != 1 {
+		t.Errorf("Ages reports accesses %v, want the entry accessed within 10ms", h.Accessed)
+	}
+}
+
+// TestSampledAccessConcurrent gives the race detector concurrent Gets sharing the read lock with readers
+// of the access metadata.
+func TestSampledAccessConcurrent(t *testing.T) {
+	c := New[int, int](4, nil, WithSampledLRU[int, int](0), WithAccessTimes[int, int]())
+	c.Put(1, 1)
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				c.Get(1)
+				c.GetEntry(1)
+				c.Ages(time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, md, _ := c.GetEntry(1); md.Hits != 400 {
+		t.Errorf("GetEntry(1) reports %d hits, want 400", md.Hits)
+	}
+}
@@ -0,0 +1,25 @@
+package lru
+
+import "hash/maphash"
+
+// WithStatsSampling reduces the overhead of observing Gets by only reporting requests for roughly one in
+// every n keys to attached shadows and to per-tenant hit and miss counts. Keys are selected by hash, so
+// every request for a selected key is observed; counts are scaled by n when reported, so they estimate
+// the totals for all keys. Because a shadow only sees about 1/n of the keyspace, it should be given
+// 1/n of the capacity it would otherwise simulate. If n is less than 2, every request is observed.
+func WithStatsSampling[K comparable, V any](n uint64) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.every = n
+		c.seed = maphash.MakeSeed()
+	}
+}
+
+// observed reports whether requests for key should be reported to shadows and tenant statistics.
+func (c *Cache[K, V]) observed(key K) bool {
+	return c.every < 2 || maphash.Comparable(c.seed, key)%c.every == 0
+}
+
+// scale returns the factor by which sampled counts must be multiplied.
+func (c *Cache[K, V]) scale() uint64 {
+	return max(c.every, 1)
+}
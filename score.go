@@ -0,0 +1,99 @@
+package lru
+
+import (
+	"errors"
+	"math/rand/v2"
+)
+
+// ErrNoVictim is returned by Put when the Cache is full and no entry may be evicted to make room for a
+// new key. The new key is not cached.
+var ErrNoVictim = errors.New("lru: no evictable entry")
+
+// EntryInfo describes the access history of a cached entry. It is passed to the scoring func set with
+// WithScorer.
+type EntryInfo struct {
+	// Age is the number of accesses to the Cache, by any key, since the entry was last accessed.
+	Age uint64
+	// Hits is the number of times the entry has been accessed since it was inserted, not counting the
+	// insertion itself.
+	Hits uint64
+}
+
+// WithScorer replaces strict LRU eviction with sampled eviction: when the Cache is full, samples entries
+// are chosen at random and the one to which score assigns the lowest value is evicted. Sampling keeps
+// the cost of each eviction constant regardless of the Cache's capacity at the price of occasionally
+// evicting an entry that a full scan would have kept; larger sample sizes approach the exact result.
+// If samples is less than 1, 5 entries are sampled.
+func WithScorer[K comparable, V any](samples int, score func(K, V, EntryInfo) float64) Option[K, V] {
+	if samples < 1 {
+		samples = 5
+	}
+	return func(c *Cache[K, V]) {
+		c.score = score
+		c.samples = samples
+	}
+}
+
+// WithCanEvict sets a func that may veto the eviction of an entry, for instance because it is still in
+// use. When canEvict returns false, the next candidate is considered instead: the next least-recently
+// used entry, or another random sample if WithScorer is also used. After attempts candidates have been
+// vetoed, Put gives up and returns ErrNoVictim. If attempts is less than 1, up to 8 candidates are tried.
+// canEvict is called while the Cache is locked.
+func WithCanEvict[K comparable, V any](attempts int, canEvict func(K, V) bool) Option[K, V] {
+	if attempts < 1 {
+		attempts = 8
+	}
+	return func(c *Cache[K, V]) {
+		c.canEvict = canEvict
+		c.attempts = attempts
+	}
+}
+
+// evictable reports whether the node at index i may be evicted.
+func (c *Cache[K, V]) evictable(i int) bool {
+	if c.pinned(c.data[i].key) || c.sparing && c.data[i].key == c.spare {
+		return false
+	}
+	return c.canEvict == nil || c.canEvict(c.data[i].key, c.data[i].val)
+}
+
+// victim returns the index of the node that should be evicted next and true, or false if every
+// candidate was vetoed.
+func (c *Cache[K, V]) victim() (int, bool) {
+	// pinned entries, and the entry spared by fit, do not use up the attempts allowed by WithCanEvict
+	attempts := max(c.attempts, 1) + len(c.pins)
+	if c.sparing {
+		attempts++
+	}
+	if c.score == nil {
+		i := c.tail
+		for range attempts {
+			if c.evictable(i) {
+				return i, true
+			}
+			if i == c.head {
+				break
+			}
+			i = c.data[i].last
+		}
+		return 0, false
+	}
+
+	best, min, vetoed := -1, 0.0, 0
+	for n := 0; n < c.samples; {
+		i := rand.IntN(c.len)
+		if !c.evictable(i) {
+			if vetoed++; vetoed >= attempts {
+				break
+			}
+			continue
+		}
+		n++
+		ptr := &c.data[i]
+		s := c.score(ptr.key, ptr.val, EntryInfo{Age: c.clock - ptr.tick, Hits: ptr.hits})
+		if best < 0 || s < min {
+			best, min = i, s
+		}
+	}
+	return best, best >= 0
+}
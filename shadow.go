@@ -0,0 +1,99 @@
+package lru
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// A ShadowPolicy simulates an alternative eviction policy over the stream of keys requested from a Cache.
+// It only ever sees keys, never values.
+type ShadowPolicy[K comparable] interface {
+	// Access records a request for key and reports whether the policy would have served it from cache.
+	// On a miss, the policy is expected to admit the key as if it had been loaded and inserted.
+	Access(key K) bool
+}
+
+// A Shadow reports how a ShadowPolicy attached to a Cache would have performed compared to the Cache itself.
+// Its methods are safe to call concurrently with operations on the Cache.
+type Shadow[K comparable] struct {
+	policy ShadowPolicy[K]
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	live   atomic.Uint64
+	scale  uint64 // see WithStatsSampling
+}
+
+// Hits returns the number of requests the shadow policy would have served from cache.
+func (s *Shadow[K]) Hits() uint64 { return s.hits.Load() * s.scale }
+
+// Misses returns the number of requests the shadow policy would have missed.
+func (s *Shadow[K]) Misses() uint64 { return s.misses.Load() * s.scale }
+
+// HitRate returns the fraction of observed requests the shadow policy would have served from cache, or 0
+// if no requests have been observed.
+func (s *Shadow[K]) HitRate() float64 {
+	hits, misses := s.hits.Load(), s.misses.Load()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// LiveHitRate returns the fraction of the same observed requests that the Cache actually served, or 0 if
+// no requests have been observed.
+func (s *Shadow[K]) LiveHitRate() float64 {
+	hits, misses := s.hits.Load(), s.misses.Load()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(s.live.Load()) / float64(hits+misses)
+}
+
+// observe feeds key to the shadow policy. live reports whether the Cache itself served the request.
+func (s *Shadow[K]) observe(key K, live bool) {
+	if s.policy.Access(key) {
+		s.hits.Add(1)
+	} else {
+		s.misses.Add(1)
+	}
+	if live {
+		s.live.Add(1)
+	}
+}
+
+// AttachShadow attaches p to the Cache and returns a Shadow that reports its performance. Every subsequent
+// call to Get is mirrored to p while the Cache's lock is held, so p need not be concurrency-safe, but it
+// should be cheap.
+func (c *Cache[K, V]) AttachShadow(p ShadowPolicy[K]) *Shadow[K] {
+	c.lock()
+	defer c.unlock()
+	s := &Shadow[K]{policy: p, scale: c.scale()}
+	c.shadows = append(c.shadows, s)
+	return s
+}
+
+// DetachShadow stops mirroring requests to s. The counters of s remain readable after it is detached.
+func (c *Cache[K, V]) DetachShadow(s *Shadow[K]) {
+	c.lock()
+	defer c.unlock()
+	c.shadows = slices.DeleteFunc(c.shadows, func(t *Shadow[K]) bool { return t == s })
+}
+
+// shadowLRU is a ShadowPolicy that simulates a keys-only LRU cache.
+type shadowLRU[K comparable] struct {
+	c *Cache[K, struct{}]
+}
+
+// NewShadowLRU returns a ShadowPolicy that simulates an LRU Cache with a capacity of cap items. Attaching
+// it to a live Cache with a different capacity shows how resizing the Cache would affect its hit rate.
+func NewShadowLRU[K comparable](cap uint64) ShadowPolicy[K] {
+	return shadowLRU[K]{c: New[K, struct{}](cap, nil)}
+}
+
+func (s shadowLRU[K]) Access(key K) bool {
+	if _, ok := s.c.Get(key); ok {
+		return true
+	}
+	s.c.Put(key, struct{}{})
+	return false
+}
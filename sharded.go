@@ -0,0 +1,142 @@
+package lru
+
+import (
+	"errors"
+	"hash/maphash"
+	"iter"
+	"runtime"
+)
+
+// A ShardedCache spreads its entries across a number of independent Caches, or shards, chosen by a hash
+// of each key, so that operations on keys in different shards do not contend for the same mutex. Each
+// shard evicts its own least-recently used entry when full, so recency is only tracked per shard, and a
+// skewed distribution of keys may cause evictions before the ShardedCache as a whole is full. A
+// ShardedCache should not be copied.
+type ShardedCache[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []*Cache[K, V]
+}
+
+var _ Cacher[int, int] = (*ShardedCache[int, int])(nil)
+
+// NewSharded creates a new ShardedCache of n shards that together hold up to cap items. If n is less than
+// 1, a shard is created for each of GOMAXPROCS. If cap is less than n, only cap shards are created, so that
+// none is left without room. evict and opts are passed to New for every shard.
+func NewSharded[K comparable, V any](n int, cap uint64, evict func(K, V) error, opts ...Option[K, V]) *ShardedCache[K, V] {
+	if n < 1 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	n = int(min(uint64(n), max(cap, 1)))
+	s := &ShardedCache[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*Cache[K, V], n),
+	}
+	per, rem := cap/uint64(n), cap%uint64(n)
+	for i := range s.shards {
+		size := per
+		if uint64(i) < rem {
+			size++
+		}
+		s.shards[i] = New(size, evict, opts...)
+	}
+	return s
+}
+
+// shard returns the shard that holds key.
+func (s *ShardedCache[K, V]) shard(key K) *Cache[K, V] {
+	return s.shards[maphash.Comparable(s.seed, key)%uint64(len(s.shards))]
+}
+
+// Get returns the cached value associated with key and a bool, which is true if the key was found and
+// false otherwise.
+func (s *ShardedCache[K, V]) Get(key K) (V, bool) {
+	return s.shard(key).Get(key)
+}
+
+// Peek is like Get, but does not update the recency of the entry.
+func (s *ShardedCache[K, V]) Peek(key K) (V, bool) {
+	return s.shard(key).Peek(key)
+}
+
+// Put adds a key-value pair to the shard that holds key, evicting that shard's least-recently used entry
+// if it is full. It returns any error as for Cache.Put.
+func (s *ShardedCache[K, V]) Put(key K, val V) error {
+	return s.shard(key).Put(key, val)
+}
+
+// GetOrPutFunc is like Cache.GetOrPutFunc, and locks only the shard that holds key.
+func (s *ShardedCache[K, V]) GetOrPutFunc(key K, fn func() V) (V, bool, error) {
+	return s.shard(key).GetOrPutFunc(key, fn)
+}
+
+// Remove deletes the entry for key as for Cache.Remove.
+func (s *ShardedCache[K, V]) Remove(key K) (V, bool, error) {
+	return s.shard(key).Remove(key)
+}
+
+// Len returns the number of entries held by all shards, including expired entries that have not yet
+// been removed. Shards are counted one at a time, so the result may not reflect any single moment if
+// the ShardedCache is modified concurrently.
+func (s *ShardedCache[K, V]) Len() int {
+	var n int
+	for _, c := range s.shards {
+		c.rlock()
+		n += c.len
+		c.runlock()
+	}
+	return n
+}
+
+// Clear clears every shard, returning any errors returned by the evict func.
+func (s *ShardedCache[K, V]) Clear() error {
+	var err error
+	for _, c := range s.shards {
+		err = errors.Join(err, c.Clear())
+	}
+	return err
+}
+
+// Close closes every shard.
+func (s *ShardedCache[K, V]) Close() error {
+	var err error
+	for _, c := range s.shards {
+		err = errors.Join(err, c.Close())
+	}
+	return err
+}
+
+// All returns an iter.Seq2 that iterates over the entries of every shard in turn. Each shard is read
+// locked only while its own entries are iterated over.
+func (s *ShardedCache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, c := range s.shards {
+			for key, val := range c.All() {
+				if !yield(key, val) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Keys returns an iter.Seq that iterates over the keys of every shard in turn.
+func (s *ShardedCache[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range s.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iter.Seq that iterates over the values of every shard in turn.
+func (s *ShardedCache[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, val := range s.All() {
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package lru
+
+import (
+	"errors"
+	"time"
+)
+
+// A Snapshot is a copy of the contents of a Cache that can be used to recreate it with NewFromSnapshot,
+// for instance after a restart. Its fields are exported, so it can be encoded with encoding/gob or
+// encoding/json as long as K and V can.
+type Snapshot[K comparable, V any] struct {
+	Cap     uint64
+	Entries []SnapshotEntry[K, V] // from the least to the most recently used
+}
+
+// A SnapshotEntry is a single entry of a Snapshot.
+type SnapshotEntry[K comparable, V any] struct {
+	Key K
+	Val V
+	TTL time.Duration `json:",omitempty"` // the time the entry had left to live, or 0 if it does not expire
+}
+
+// Snapshot returns a Snapshot of the Cache's capacity and unexpired entries. Values are copied as by
+// assignment, so values that refer to shared memory, such as slices and pointers, are shared with the
+// Cache.
+func (c *Cache[K, V]) Snapshot() Snapshot[K, V] {
+	c.rlock()
+	defer c.runlock()
+	s := Snapshot[K, V]{
+		Cap:     c.cap,
+		Entries: make([]SnapshotEntry[K, V], 0, c.len),
+	}
+	now := c.now()
+	for i, n := c.tail, 0; n < c.len; i, n = c.data[i].last, n+1 {
+		ptr := &c.data[i]
+		if ptr.exp != 0 && now >= ptr.exp {
+			continue
+		}
+		e := SnapshotEntry[K, V]{Key: ptr.key, Val: ptr.val}
+		if ptr.exp != 0 {
+			e.TTL = time.Duration(ptr.exp - now)
+		}
+		s.Entries = append(s.Entries, e)
+	}
+	return s
+}
+
+// NewFromSnapshot creates a new Cache with the capacity and entries of s, in the same recency order. evict
+// and opts are as for New; entries whose TTL was 0 do not expire, regardless of WithTTL. If the options
+// make the Cache too small for all of the entries, the least recently used ones are evicted, and
+// NewFromSnapshot returns the Cache along with any errors returned by evict.
+func NewFromSnapshot[K comparable, V any](s Snapshot[K, V], evict func(K, V) error, opts ...Option[K, V]) (*Cache[K, V], error) {
+	c := New(s.Cap, evict, opts...)
+	c.lock()
+	defer c.unlock()
+	var err error
+	if c.cap == 0 {
+		return c, err
+	}
+	for _, e := range s.Entries {
+		err = errors.Join(err, c.put(e.Key, e.Val, c.stamp(e.TTL)))
+	}
+	return c, err
+}
@@ -0,0 +1,42 @@
+package lru
+
+// Stats summarizes the activity of a Cache since it was created or its statistics were last reset.
+type Stats struct {
+	Hits       uint64 // lookups that found their key
+	Misses     uint64 // lookups that did not find their key
+	Evictions  uint64 // entries evicted to make room for others
+	Insertions uint64 // keys added to the Cache, not counting updates of cached keys
+	Len        int    // number of entries currently cached, including expired ones not yet removed
+}
+
+// Stats returns the Cache's statistics. Lookups are counted by Get and the other methods that update
+// the recency of the entry they find, but not by Peek or iteration. Unlike the statistics of shadows and
+// tenants, they are not affected by WithStatsSampling.
+func (c *Cache[K, V]) Stats() Stats {
+	c.rlock()
+	defer c.runlock()
+	return Stats{
+		Hits:       c.hits.Load(),
+		Misses:     c.misses.Load(),
+		Evictions:  c.evictions.Load(),
+		Insertions: c.insertions.Load(),
+		Len:        c.len,
+	}
+}
+
+// ResetStats sets the Cache's hit, miss, eviction, and insertion counts to zero.
+func (c *Cache[K, V]) ResetStats() {
+	c.hits.Store(0)
+	c.misses.Store(0)
+	c.evictions.Store(0)
+	c.insertions.Store(0)
+}
+
+// count records the outcome of a lookup.
+func (c *Cache[K, V]) count(hit bool) {
+	if hit {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+}
@@ -0,0 +1,138 @@
+package lru
+
+// TenantStats describes the entries and activity of one tenant of a Cache (see WithTenantQuota).
+type TenantStats struct {
+	// Len is the number of entries the tenant currently has in the Cache.
+	Len int
+	// Hits and Misses count the tenant's keys requested from the Cache with Get and found or not found.
+	// They are estimates if WithStatsSampling is used.
+	Hits, Misses uint64
+	// Evictions counts the tenant's entries evicted to make room for new ones, whether because the Cache
+	// was full or because the tenant reached its quota.
+	Evictions uint64
+}
+
+// WithTenantQuota assigns every key to the tenant returned by tenant and limits each tenant t to quota(t)
+// entries. A Put that would take a tenant over its quota evicts that tenant's least-recently used entry
+// instead of the Cache's, so one tenant cannot displace everyone else's entries; a Put that would exceed
+// a quota of 0 returns ErrNoVictim. Finding a tenant's least-recently used entry walks the recency list
+// from the tail, calling tenant for each entry it passes. Both funcs are called while the Cache is locked.
+func WithTenantQuota[K comparable, V any](tenant func(K) string, quota func(string) int) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.tenant = tenant
+		c.quota = quota
+		c.tenants = make(map[string]*TenantStats)
+	}
+}
+
+// TenantLen returns the number of entries in the Cache belonging to tenant t (see WithTenantQuota).
+func (c *Cache[K, V]) TenantLen(t string) int {
+	c.rlock()
+	defer c.runlock()
+	if s := c.tenants[t]; s != nil {
+		return s.Len
+	}
+	return 0
+}
+
+// TenantStats returns the statistics of every tenant that has had an entry in, or requested a key from,
+// the Cache (see WithTenantQuota).
+func (c *Cache[K, V]) TenantStats() map[string]TenantStats {
+	c.rlock()
+	defer c.runlock()
+	m := make(map[string]TenantStats, len(c.tenants))
+	for t, s := range c.tenants {
+		st := *s
+		st.Hits *= c.scale()
+		st.Misses *= c.scale()
+		m[t] = st
+	}
+	return m
+}
+
+// tenantStats returns the statistics of tenant t, creating them if necessary.
+func (c *Cache[K, V]) tenantStats(t string) *TenantStats {
+	s := c.tenants[t]
+	if s == nil {
+		s = new(TenantStats)
+		c.tenants[t] = s
+	}
+	return s
+}
+
+// overQuota reports whether adding an entry for key would take its tenant over quota, along with the
+// tenant.
+func (c *Cache[K, V]) overQuota(key K) (string, bool) {
+	if c.tenant == nil {
+		return "", false
+	}
+	t := c.tenant(key)
+	var n int
+	if s := c.tenants[t]; s != nil {
+		n = s.Len
+	}
+	return t, n >= c.quota(t)
+}
+
+// track counts a new entry for key towards its tenant's quota.
+func (c *Cache[K, V]) track(key K) {
+	if c.tenant != nil {
+		c.tenantStats(c.tenant(key)).Len++
+	}
+}
+
+// untrack stops counting the entry for key towards its tenant's quota. If evicted is true, the entry is
+// counted as an eviction.
+func (c *Cache[K, V]) untrack(key K, evicted bool) {
+	if c.tenant == nil {
+		return
+	}
+	s := c.tenantStats(c.tenant(key))
+	s.Len--
+	if evicted {
+		s.Evictions++
+	}
+}
+
+// request counts a Get of key by its tenant.
+func (c *Cache[K, V]) request(key K, hit bool) {
+	if c.tenant == nil {
+		return
+	}
+	s := c.tenantStats(c.tenant(key))
+	if hit {
+		s.Hits++
+	} else {
+		s.Misses++
+	}
+}
+
+// tenantVictim returns the index of tenant t's least-recently used evictable entry.
+func (c *Cache[K, V]) tenantVictim(t string) (int, bool) {
+	if c.len == 0 {
+		return 0, false
+	}
+	for i := c.tail; ; i = c.data[i].last {
+		if c.tenant(c.data[i].key) == t && c.evictable(i) {
+			return i, true
+		}
+		if i == c.head {
+			return 0, false
+		}
+	}
+}
+
+// Classify counts the unexpired entries of the Cache in each of the classes returned by classify, such
+// as a key prefix, in a single pass under a read lock. classify must not call methods on the Cache.
+func (c *Cache[K, V]) Classify(classify func(K, V) string) map[string]int {
+	c.rlock()
+	defer c.runlock()
+	m := make(map[string]int)
+	live := c.live()
+	for i := range c.data[:c.len] {
+		if n := &c.data[i]; live.ok(n) {
+			m[classify(n.key, n.val)]++
+		}
+	}
+	return m
+}
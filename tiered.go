@@ -0,0 +1,73 @@
+package lru
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// A Tiered composes two caches into a hierarchy: a small, fast first level, l1, in front of a larger
+// second level, l2, such as a ShardedCache. Get checks l1 first and, on a miss, l2; entries found in l2
+// are promoted into l1. Put writes to both levels, so every entry in l1 is also in l2 unless l2 has since
+// evicted it. Either level may itself be any Cacher, including another Tiered.
+type Tiered[K comparable, V any] struct {
+	l1, l2 Cacher[K, V]
+	onErr  func(error)
+
+	l1Hits atomic.Uint64
+	l2Hits atomic.Uint64
+	misses atomic.Uint64
+}
+
+var _ Cacher[int, int] = (*Tiered[int, int])(nil)
+
+// TieredStats counts the lookups made through a Tiered.
+type TieredStats struct {
+	L1Hits uint64 // lookups served by the first level
+	L2Hits uint64 // lookups that missed the first level but were served by the second
+	Misses uint64 // lookups that missed both levels
+}
+
+// NewTiered creates a new Tiered with l1 in front of l2. If onErr is non-nil, it is called with any error
+// returned by l1's Put when Get promotes an entry, since Get cannot return it.
+func NewTiered[K comparable, V any](l1, l2 Cacher[K, V], onErr func(error)) *Tiered[K, V] {
+	return &Tiered[K, V]{l1: l1, l2: l2, onErr: onErr}
+}
+
+// Get returns the value associated with key in the first level that holds it and a bool, which is true
+// if the key was found and false otherwise. A value found only in the second level is added to the first.
+func (t *Tiered[K, V]) Get(key K) (V, bool) {
+	if val, ok := t.l1.Get(key); ok {
+		t.l1Hits.Add(1)
+		return val, true
+	}
+	val, ok := t.l2.Get(key)
+	if !ok {
+		t.misses.Add(1)
+		return val, false
+	}
+	t.l2Hits.Add(1)
+	if err := t.l1.Put(key, val); err != nil && t.onErr != nil {
+		t.onErr(err)
+	}
+	return val, true
+}
+
+// Put adds a key-value pair to both levels, returning the errors returned by either.
+func (t *Tiered[K, V]) Put(key K, val V) error {
+	return errors.Join(t.l2.Put(key, val), t.l1.Put(key, val))
+}
+
+// Clear clears both levels, returning the errors returned by either.
+func (t *Tiered[K, V]) Clear() error {
+	return errors.Join(t.l1.Clear(), t.l2.Clear())
+}
+
+// Stats returns the number of lookups made through the Tiered so far, broken down by the level that
+// served them.
+func (t *Tiered[K, V]) Stats() TieredStats {
+	return TieredStats{
+		L1Hits: t.l1Hits.Load(),
+		L2Hits: t.l2Hits.Load(),
+		Misses: t.misses.Load(),
+	}
+}
@@ -0,0 +1,107 @@
+package lru
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+// sketchRows is the number of rows of a sketch, each of which counts a key under a different hash.
+const sketchRows = 4
+
+// sketchMax is the value at which a sketch's counters saturate.
+const sketchMax = 15
+
+// A sketch is a count-min sketch that estimates how often each key has been requested recently. Once it
+// has counted ten times as many requests as it has counters per row, it halves every counter, so that
+// keys that were popular long ago are forgotten.
+type sketch[K comparable] struct {
+	seed  maphash.Seed
+	rows  [sketchRows][]uint8
+	mask  uint64
+	count int // requests counted since the counters were last halved
+}
+
+// WithTinyLFU adds a TinyLFU admission filter to the Cache, which keeps an estimate of how often every key
+// has been requested recently, whether or not it is cached. When the Cache is full, a new key is only
+// added if it has been requested more often than the entry it would evict; otherwise, the write returns
+// ErrNotAdmitted, as for WithAdmission. This keeps keys that are requested once from displacing popular
+// ones, which raises the hit rate for skewed workloads. The estimates take a few bytes per entry of
+// capacity and are updated by every lookup and write, so with WithSampledLRU, Get takes the write lock.
+// Resize starts the estimates over if the capacity changes significantly.
+func WithTinyLFU[K comparable, V any]() Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.freq = &sketch[K]{seed: maphash.MakeSeed()}
+	}
+}
+
+// frequent reports whether key may be added to the Cache as far as the TinyLFU filter is concerned.
+func (c *Cache[K, V]) frequent(key K) bool {
+	if uint64(c.len) < c.cap {
+		return true
+	}
+	i, ok := c.victim()
+	if !ok {
+		// adding the key fails regardless
+		return true
+	}
+	return c.freq.estimate(key) > c.freq.estimate(c.data[i].key)
+}
+
+// add counts a request for key. The counters are allocated on first use, with one per row for each entry
+// of a Cache of capacity cap.
+func (s *sketch[K]) add(key K, cap uint64) {
+	if s.rows[0] == nil {
+		n := sketchWidth(cap)
+		for i := range s.rows {
+			s.rows[i] = make([]uint8, n)
+		}
+		s.mask = n - 1
+	}
+	h1, h2 := s.hash(key)
+	for i := range s.rows {
+		if j := (h1 + uint64(i)*h2) & s.mask; s.rows[i][j] < sketchMax {
+			s.rows[i][j]++
+		}
+	}
+	if s.count++; s.count >= 10*len(s.rows[0]) {
+		for i := range s.rows {
+			for j := range s.rows[i] {
+				s.rows[i][j] /= 2
+			}
+		}
+		s.count /= 2
+	}
+}
+
+// resize adapts the sketch to a Cache of capacity cap. If the number of counters needed changes, the
+// counters are reallocated on next use, and the requests counted so far are forgotten.
+func (s *sketch[K]) resize(cap uint64) {
+	if s.rows[0] != nil && uint64(len(s.rows[0])) != sketchWidth(cap) {
+		s.rows = [sketchRows][]uint8{}
+		s.count = 0
+	}
+}
+
+// sketchWidth returns the number of counters per row of a sketch for a Cache of capacity cap.
+func sketchWidth(cap uint64) uint64 {
+	return 1 << bits.Len64(max(cap, 16)-1)
+}
+
+// estimate returns the estimated number of recent requests for key.
+func (s *sketch[K]) estimate(key K) uint8 {
+	if s.rows[0] == nil {
+		return 0
+	}
+	h1, h2 := s.hash(key)
+	est := uint8(sketchMax)
+	for i := range s.rows {
+		est = min(est, s.rows[i][(h1+uint64(i)*h2)&s.mask])
+	}
+	return est
+}
+
+// hash returns the two hashes of key from which the index of its counter in each row is derived.
+func (s *sketch[K]) hash(key K) (uint64, uint64) {
+	h := maphash.Comparable(s.seed, key)
+	return h, h>>32 | 1
+}
@@ -0,0 +1,45 @@
+package lru
+
+import "errors"
+
+// ErrBusy is returned by TryGet and TryPut when the Cache's mutex is held by another goroutine.
+var ErrBusy = errors.New("lru: cache is busy")
+
+// TryGet is like Get, but if the Cache's mutex cannot be acquired immediately, it returns ErrBusy
+// instead of waiting, so that a latency-sensitive caller can fall back to recomputing the value.
+func (c *Cache[K, V]) TryGet(key K) (V, bool, error) {
+	if c.sampled {
+		if !c.tryrlock() {
+			return *new(V), false, ErrBusy
+		}
+		val, ok, done := c.shared(key)
+		c.runlock()
+		if done {
+			return val, ok, nil
+		}
+	}
+	if !c.trylock() {
+		return *new(V), false, ErrBusy
+	}
+	defer c.unlock()
+	val, ok := c.get(key)
+	return val, ok, nil
+}
+
+// TryPut is like Put, but if the Cache's mutex cannot be acquired immediately, it returns ErrBusy
+// instead of waiting, and the key-value pair is not added.
+func (c *Cache[K, V]) TryPut(key K, val V) error {
+	if !c.trylock() {
+		return ErrBusy
+	}
+	defer c.unlock()
+	var err error
+
+	if c.cap == 0 {
+		return err
+	}
+	if c.evict != nil {
+		c.retry(false)
+	}
+	return c.put(key, val, c.stamp(c.ttl))
+}
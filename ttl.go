@@ -0,0 +1,210 @@
+package lru
+
+import (
+	"errors"
+	"time"
+)
+
+// WithTTL sets the time-to-live of entries added by Put and the other methods that do not take a TTL of
+// their own. An entry expires ttl after it was last written; expired entries are treated as missing and
+// are removed, with the Expired reason (see WithEvictReason), when they are next looked up. Since Get
+// has no error result, errors returned by evict for entries it removes are discarded unless
+// WithEvictRetry is used. Iterators skip expired entries. If ttl is not positive, entries do not expire.
+func WithTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.ttl = ttl
+	}
+}
+
+// WithMaxAge limits how long any entry may be cached after it was last written to d, whatever its TTL and
+// however often it is accessed: entries that would not otherwise expire, and entries given a longer TTL
+// with PutWithTTL or WithTTL, expire d after they were written. This suits rules that forbid serving data
+// older than a fixed age. If d is not positive, it has no effect.
+func WithMaxAge[K comparable, V any](d time.Duration) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.maxAge = d
+	}
+}
+
+// WithValidator sets a func that is consulted whenever Get, or another method that updates the recency
+// of an entry, finds one. Entries for which validate returns false, such as closed connections or
+// revoked tokens, are treated as missing and removed, with the Invalid reason, so that loaders such as
+// GetOrCompute replace them. Since Get has no error result, errors returned by evict for such entries
+// are discarded unless WithEvictRetry is used. validate is called while the Cache is locked, possibly
+// only for reading, so it must not call methods on the Cache.
+func WithValidator[K comparable, V any](validate func(K, V) bool) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.validate = validate
+	}
+}
+
+// PutWithTTL behaves like Put but sets the time-to-live of the entry to ttl instead of the Cache's
+// default. If ttl is not positive, the entry does not expire, unless WithMaxAge is used.
+func (c *Cache[K, V]) PutWithTTL(key K, val V, ttl time.Duration) error {
+	c.lock()
+	defer c.unlock()
+	if c.cap == 0 {
+		return nil
+	}
+	if c.evict != nil {
+		c.retry(false)
+	}
+	return c.put(key, val, c.stamp(ttl))
+}
+
+// now returns the time elapsed since the Cache was created. It is measured with the monotonic clock and
+// is always positive, so that an expiration time of 0 can mean "never".
+func (c *Cache[K, V]) now() int64 {
+	return int64(time.Since(c.epoch)) + 1
+}
+
+// A stamp holds the times recorded for a write.
+type stamp struct {
+	at  int64 // see node.at
+	exp int64 // see node.exp
+}
+
+// stamp returns the times of a write made now of an entry with the given ttl, as limited by WithMaxAge.
+// It reads the clock at most once, and not at all if the entry does not expire and the Cache does not
+// record write times.
+func (c *Cache[K, V]) stamp(ttl time.Duration) stamp {
+	if c.maxAge > 0 && (ttl <= 0 || ttl > c.maxAge) {
+		ttl = c.maxAge
+	}
+	if ttl <= 0 && !c.stamped {
+		return stamp{}
+	}
+	s := stamp{at: c.now()}
+	if ttl > 0 {
+		s.exp = s.at + int64(ttl)
+	}
+	return s
+}
+
+// expired reports whether the node at index i has expired.
+func (c *Cache[K, V]) expired(i int) bool {
+	exp := c.data[i].exp
+	return exp != 0 && c.now() >= exp
+}
+
+// find returns the index of the node for key and true, or false if key is not cached. If the node has
+// expired, find removes it and returns false along with any error from discarding it.
+func (c *Cache[K, V]) find(key K) (int, bool, error) {
+	i, ok := c.keys[key]
+	if !ok || !c.expired(i) {
+		return i, ok, nil
+	}
+	n := c.data[i]
+	c.remove(i, false)
+	return 0, false, c.discard(n.key, n.val, Expired)
+}
+
+// WithJanitor starts a background goroutine that removes expired entries every interval, so that entries
+// that are never looked up again do not linger until they are evicted. If onErr is non-nil, it is called
+// with any errors returned by the evict func for the entries removed. The Cache must be closed with Close
+// to stop the goroutine.
+func WithJanitor[K comparable, V any](interval time.Duration, onErr func(error)) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.janitor = interval
+		if onErr != nil {
+			c.onErr = onErr
+		}
+	}
+}
+
+// RemoveExpired removes all expired entries from the Cache, calling the evict func (if it exists) for
+// each, and returns any errors it returned.
+func (c *Cache[K, V]) RemoveExpired() error {
+	c.lock()
+	defer c.unlock()
+	return c.sweep()
+}
+
+// ExpireBefore removes all entries last written before t, including quarantined ones, calling the evict
+// func (if it exists) with the Expired reason for each. Entries whose write time was not recorded (see
+// WithWriteTimes) are removed too, since they may have been written before t. It returns the number of
+// cached entries removed and any errors returned by evict.
+func (c *Cache[K, V]) ExpireBefore(t time.Time) (int, error) {
+	c.lock()
+	defer c.unlock()
+	return c.expireBefore(t)
+}
+
+// expireBefore implements ExpireBefore for a caller holding the write lock.
+func (c *Cache[K, V]) expireBefore(t time.Time) (int, error) {
+	cutoff := int64(t.Sub(c.epoch)) + 1
+	var err error
+	var removed int
+	c.beginBatch()
+	// walk backwards so that the node moved into each vacated slot has already been checked
+	for i := c.len - 1; i >= 0; i-- {
+		n := c.data[i]
+		if n.at != 0 && n.at >= cutoff {
+			continue
+		}
+		c.remove(i, false)
+		err = errors.Join(err, c.discard(n.key, n.val, Expired))
+		removed++
+	}
+	for i := len(c.quarantined) - 1; i >= 0; i-- {
+		if at := c.quarantined[i].at; at == 0 || at < cutoff {
+			err = errors.Join(err, c.finalize(i, Expired))
+		}
+	}
+	return removed, errors.Join(err, c.endBatch())
+}
+
+// sweep removes all expired entries.
+func (c *Cache[K, V]) sweep() error {
+	var err error
+	now := c.now()
+	c.beginBatch()
+	// walk backwards so that the node moved into each vacated slot has already been checked
+	for i := c.len - 1; i >= 0; i-- {
+		n := c.data[i]
+		if n.exp == 0 || now < n.exp {
+			continue
+		}
+		c.remove(i, false)
+		err = errors.Join(err, c.discard(n.key, n.val, Expired))
+	}
+	return errors.Join(err, c.endBatch())
+}
+
+func (c *Cache[K, V]) janitorLoop(stop <-chan struct{}) {
+	t := time.NewTicker(c.janitor)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := c.RemoveExpired(); err != nil && c.onErr != nil {
+				c.onErr(err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// A liveness reports whether nodes have not expired, evaluated at a single point in time. It is a value
+// rather than a closure so that iterators using it do not allocate.
+type liveness[K comparable, V any] struct {
+	c   *Cache[K, V]
+	now int64 // 0 until the time is first needed
+}
+
+// live returns a liveness for the current time.
+func (c *Cache[K, V]) live() liveness[K, V] {
+	return liveness[K, V]{c: c}
+}
+
+// ok reports whether n has not expired.
+func (l *liveness[K, V]) ok(n *node[K, V]) bool {
+	if n.exp == 0 {
+		return true
+	}
+	if l.now == 0 {
+		l.now = l.c.now()
+	}
+	return l.now < n.exp
+}
@@ -0,0 +1,39 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpireBefore(t *testing.T) {
+	c := New[int, int](4, nil, WithWriteTimes[int, int]())
+	c.Put(1, 1)
+	time.Sleep(time.Millisecond)
+	cutoff := time.Now()
+	c.Put(2, 2)
+	if n, err := c.ExpireBefore(cutoff); n != 1 || err != nil {
+		t.Fatalf("ExpireBefore = %d, %v; want 1, nil", n, err)
+	}
+	if c.Contains(1) || !c.Contains(2) {
+		t.Error("ExpireBefore did not remove exactly the entry written before the cutoff")
+	}
+	if _, md, _ := c.GetEntry(2); md.Written.Before(cutoff) {
+		t.Errorf("GetEntry(2) reports it was written at %v, before %v", md.Written, cutoff)
+	}
+}
+
+// TestExpireBeforeUnrecorded checks that entries without a recorded write time are treated as stale.
+func TestExpireBeforeUnrecorded(t *testing.T) {
+	c := New[int, int](4, nil)
+	c.Put(1, 1)
+	if n, _ := c.ExpireBefore(time.Now().Add(-time.Hour)); n != 1 {
+		t.Errorf("ExpireBefore removed %d entries, want 1", n)
+	}
+	c.Put(1, 1)
+	if _, md, _ := c.GetEntry(1); !md.Written.IsZero() {
+		t.Errorf("GetEntry(1) reports a write time of %v without WithWriteTimes", md.Written)
+	}
+	if h := c.Ages(time.Second); h.Inserted != nil {
+		t.Errorf("Ages reports insertions %v without WithWriteTimes", h.Inserted)
+	}
+}
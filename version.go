@@ -0,0 +1,46 @@
+package lru
+
+import "errors"
+
+// GetVersion behaves like Get but also returns the version of the cached value. Every write to the Cache
+// assigns the written entry a new version greater than any assigned before, so a version identifies one
+// particular write of one particular key.
+func (c *Cache[K, V]) GetVersion(key K) (V, uint64, bool) {
+	c.lock()
+	defer c.unlock()
+	i, ok := c.lookup(key)
+	if !ok {
+		return *new(V), 0, false
+	}
+	c.touch(i)
+	c.promote(i)
+	return c.data[i].val, c.data[i].ver, true
+}
+
+// PutIfVersion adds a key-value pair to the Cache only if the cached entry for key has version expected,
+// as returned by GetVersion, or if expected is 0 and key is not cached. It reports whether the value was
+// stored, along with any error returned as for Put. The new version can be obtained with GetVersion.
+func (c *Cache[K, V]) PutIfVersion(key K, val V, expected uint64) (bool, error) {
+	c.lock()
+	defer c.unlock()
+	i, ok, err := c.find(key)
+	if ok && c.data[i].ver != expected || !ok && expected != 0 || c.cap == 0 {
+		return false, err
+	}
+	if c.evict != nil {
+		c.retry(false)
+	}
+	// put may fail on a key that stays cached, so check that it wrote a new version
+	before := c.version
+	err = errors.Join(err, c.put(key, val, c.stamp(c.ttl)))
+	i, ok = c.keys[key]
+	return ok && c.version != before && c.data[i].ver == c.version, err
+}
+
+// Version returns a counter that grows every time an entry is added to, updated in, or removed from the
+// Cache, so that a caller can tell whether anything has changed since it last looked without comparing
+// contents. Accesses do not change it. Version does not take the Cache's lock, so it may lag behind a
+// change that is in progress.
+func (c *Cache[K, V]) Version() uint64 {
+	return c.changes.Load()
+}
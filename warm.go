@@ -0,0 +1,83 @@
+package lru
+
+import (
+	"sync"
+	"time"
+)
+
+// A WarmProbe reports whether a Cache has warmed up, so that a readiness check can hold back traffic from
+// an instance whose Cache is still cold. A Cache is warm once it is filled to a given fraction of its
+// capacity, or once its hit rate has stayed at or above a threshold for a given duration. Once a
+// WarmProbe has reported the Cache warm, it always does. A WarmProbe is safe for concurrent use.
+type WarmProbe[K comparable, V any] struct {
+	c       *Cache[K, V]
+	hitRate float64
+	hold    time.Duration
+	fill    float64
+
+	m      sync.Mutex
+	hits   uint64 // the Cache's hit and miss counts at the last check
+	misses uint64
+	last   time.Time // the time of the last check
+	since  time.Time // the start of the current run at or above hitRate, or zero
+	warm   bool
+}
+
+// WarmProbe returns a WarmProbe that considers the Cache warm once it holds at least fill times its
+// capacity in entries, or once its hit rate, as measured between successive calls to Warm, has been at
+// least hitRate for hold. Either condition is disabled by a value of 0. The hit rate is computed from
+// the counts reported by Stats, so calling ResetStats restarts the measurement.
+func (c *Cache[K, V]) WarmProbe(hitRate float64, hold time.Duration, fill float64) *WarmProbe[K, V] {
+	c.rlock()
+	defer c.runlock()
+	return &WarmProbe[K, V]{
+		c:       c,
+		hitRate: hitRate,
+		hold:    hold,
+		fill:    fill,
+		hits:    c.hits.Load(),
+		misses:  c.misses.Load(),
+		last:    time.Now(),
+	}
+}
+
+// Warm reports whether the Cache is warm. It should be called periodically, for instance by a readiness
+// check, since the hit rate is measured over the interval between calls.
+func (p *WarmProbe[K, V]) Warm() bool {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.warm {
+		return true
+	}
+
+	c := p.c
+	c.rlock()
+	hits, misses, n, cap := c.hits.Load(), c.misses.Load(), c.len, c.cap
+	c.runlock()
+	now := time.Now()
+	if hits < p.hits || misses < p.misses {
+		// the statistics were reset
+		p.hits, p.misses = 0, 0
+	}
+	dh, dm := hits-p.hits, misses-p.misses
+	p.hits, p.misses = hits, misses
+	start := p.last
+	p.last = now
+
+	if p.fill > 0 && cap > 0 && float64(n) >= p.fill*float64(cap) {
+		p.warm = true
+		return true
+	}
+	if p.hitRate <= 0 || dh+dm == 0 {
+		return false
+	}
+	if float64(dh)/float64(dh+dm) < p.hitRate {
+		p.since = time.Time{}
+		return false
+	}
+	if p.since.IsZero() {
+		p.since = start
+	}
+	p.warm = now.Sub(p.since) >= p.hold
+	return p.warm
+}
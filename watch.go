@@ -0,0 +1,99 @@
+package lru
+
+import (
+	"slices"
+	"time"
+)
+
+// A WatchKind identifies the kind of a WatchEvent.
+type WatchKind int
+
+const (
+	// WatchInserted events report that a key was added to the Cache.
+	WatchInserted WatchKind = iota
+	// WatchPromoted events report an access of a cached key, which moves it up the recency order.
+	WatchPromoted
+	// WatchUpdated events report that the value of a cached key was replaced.
+	WatchUpdated
+	// WatchRemoved events report that a key left the Cache, for the Reason given in the event.
+	WatchRemoved
+)
+
+func (k WatchKind) String() string {
+	switch k {
+	case WatchInserted:
+		return "inserted"
+	case WatchPromoted:
+		return "promoted"
+	case WatchUpdated:
+		return "updated"
+	case WatchRemoved:
+		return "removed"
+	}
+	return "unknown"
+}
+
+// A WatchEvent describes a change to a watched entry of a Cache.
+type WatchEvent[K comparable, V any] struct {
+	Kind   WatchKind
+	Key    K
+	Val    V      // the entry's value after the change, or the value removed
+	Reason Reason // why the entry was removed; only set for WatchRemoved
+	Time   time.Time
+}
+
+type watch[K comparable, V any] struct {
+	removals bool // whether only WatchRemoved events are reported
+	match    func(K) bool
+	fn       func(WatchEvent[K, V])
+}
+
+// Watch calls fn with an event for every insertion, access, update, and removal of an entry whose key
+// satisfies match, until the returned stop func is called, so that the history of a few keys can be
+// traced without instrumenting every caller. A write to a cached key is reported as an access followed by
+// an update, unless WithUntouchedWrites is set. An entry evicted into quarantine (see WithQuarantine) is
+// reported as removed, and as inserted again if it is rescued. match and fn are called while the Cache is
+// locked, so they must not call methods on the Cache.
+func (c *Cache[K, V]) Watch(match func(K) bool, fn func(WatchEvent[K, V])) (stop func()) {
+	w := &watch[K, V]{match: match, fn: fn}
+	c.lock()
+	c.watches = append(c.watches, w)
+	c.unlock()
+	return func() {
+		c.lock()
+		defer c.unlock()
+		for i, x := range c.watches {
+			if x == w {
+				c.watches = slices.Delete(c.watches, i, i+1)
+				return
+			}
+		}
+	}
+}
+
+// WatchKeys is like Watch, but watches the given keys.
+func (c *Cache[K, V]) WatchKeys(fn func(WatchEvent[K, V]), keys ...K) (stop func()) {
+	set := make(map[K]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	return c.Watch(func(key K) bool {
+		_, ok := set[key]
+		return ok
+	}, fn)
+}
+
+// notify reports an event to the watches that match key. Callers check that there are watches first, so
+// that unwatched Caches pay for nothing but the check.
+func (c *Cache[K, V]) notify(kind WatchKind, key K, val V, reason Reason) {
+	var now time.Time
+	for _, w := range c.watches {
+		if w.removals && kind != WatchRemoved || !w.match(key) {
+			continue
+		}
+		if now.IsZero() {
+			now = time.Now()
+		}
+		w.fn(WatchEvent[K, V]{Kind: kind, Key: key, Val: val, Reason: reason, Time: now})
+	}
+}
@@ -0,0 +1,29 @@
+package lru
+
+import "errors"
+
+// WithWatermarks makes the Cache evict in batches. When adding an entry would take the Cache above high
+// entries (or above its capacity, if that is lower), entries are first evicted until only low remain.
+// Batching amortizes the cost of the evict func over many entries, at the price of a slower Put each
+// time the high watermark is reached. The option has no effect unless low is less than high.
+func WithWatermarks[K comparable, V any](high, low uint64) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.high = high
+		c.low = low
+	}
+}
+
+// shrink evicts entries until at most n remain or no entry may be evicted.
+func (c *Cache[K, V]) shrink(n uint64) error {
+	var err error
+	c.beginBatch()
+	for uint64(c.len) > n {
+		i, ok := c.victim()
+		if !ok {
+			break
+		}
+		err = errors.Join(err, c.expel(i))
+		c.remove(i, true)
+	}
+	return errors.Join(err, c.endBatch())
+}
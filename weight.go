@@ -0,0 +1,57 @@
+package lru
+
+import "errors"
+
+// ErrTooHeavy is returned by Put when the weight of an entry alone exceeds the maximum weight of the
+// Cache. The entry is not cached.
+var ErrTooHeavy = errors.New("lru: entry exceeds maximum weight")
+
+// WithWeigher sets a func that assigns each entry a weight, such as the size of its value in bytes. An
+// entry's weight is computed when it is written. Combined with WithMaxWeight, it limits the Cache by the
+// total weight of its entries, in addition to the number of entries passed to New.
+func WithWeigher[K comparable, V any](weigh func(K, V) uint64) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.weigh = weigh
+	}
+}
+
+// WithMaxWeight limits the total weight of the entries in the Cache, as assigned by the func set with
+// WithWeigher, to n. Before a Put would exceed it, the least-recently used entries are evicted until the
+// new entry fits. If n is 0, weights are only tracked; see Weight.
+func WithMaxWeight[K comparable, V any](n uint64) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.maxWeight = n
+	}
+}
+
+// Weight returns the total weight of the entries in the Cache, including expired ones not yet removed.
+func (c *Cache[K, V]) Weight() uint64 {
+	c.rlock()
+	defer c.runlock()
+	return c.weight
+}
+
+// fit evicts entries other than the one for key until an entry of weight w fits within the maximum
+// weight of the Cache.
+func (c *Cache[K, V]) fit(w uint64, key K) error {
+	if c.maxWeight == 0 {
+		return nil
+	}
+	if w > c.maxWeight {
+		return ErrTooHeavy
+	}
+	var err error
+	c.spare, c.sparing = key, true
+	defer func() { c.spare, c.sparing = *new(K), false }()
+	c.beginBatch()
+	for c.weight+w > c.maxWeight {
+		i, ok := c.victim()
+		if !ok {
+			err = errors.Join(err, ErrNoVictim)
+			break
+		}
+		err = errors.Join(err, c.expel(i))
+		c.remove(i, true)
+	}
+	return errors.Join(err, c.endBatch())
+}